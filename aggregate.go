@@ -0,0 +1,145 @@
+package extract
+
+import (
+	extractor "github.com/aafeher/go-microdata-extract/extractors"
+	"strings"
+)
+
+// ByType returns the extracted JSON-LD nodes (map[string]any) and microdata items (extractor.MicrodataItem) grouped by
+// their type, normalized with extractor.NormalizeSchemaType, giving one view across the syntaxes.
+// Items with several types are listed under each of them, items without a type are left out. The members of the
+// @graph arrays kept by SetJSONLDPassthrough are listed as well, like with JSONLDByType.
+func (e *Extractor) ByType() map[string][]any {
+	byType := make(map[string][]any)
+
+	if nodes, ok := e.extracted[SyntaxJSONLD].([]map[string]any); ok {
+		for _, node := range jsonLDGraphNodes(nodes) {
+			for _, t := range extractor.JSONLDTypes(node) {
+				byType[t] = append(byType[t], node)
+			}
+		}
+	}
+	if items, ok := e.extracted[SyntaxMicrodata].([]extractor.MicrodataItem); ok {
+		for _, item := range items {
+			for _, t := range microdataItemTypes(item) {
+				t = extractor.NormalizeSchemaType(t)
+				byType[t] = append(byType[t], item)
+			}
+		}
+	}
+
+	return byType
+}
+
+// jsonLDGraphNodes returns nodes followed, for each node, by the members of its @graph.
+func jsonLDGraphNodes(nodes []map[string]any) []map[string]any {
+	var all []map[string]any
+	for _, node := range nodes {
+		all = append(all, node)
+		if graph, ok := node["@graph"].([]any); ok {
+			for _, member := range graph {
+				if m, ok := member.(map[string]any); ok {
+					all = append(all, m)
+				}
+			}
+		}
+	}
+
+	return all
+}
+
+// SocialProfiles returns the deduplicated profile URLs of the page entity across the syntaxes: the sameAs URLs of
+// JSON-LD Person nodes and of microdata items, the rel="me" links of the HTML, and the og:url of an Open Graph
+// profile, in this order.
+func (e *Extractor) SocialProfiles() []string {
+	var profiles []string
+
+	if nodes, ok := e.extracted[SyntaxJSONLD].([]map[string]any); ok {
+		for _, node := range jsonLDGraphNodes(nodes) {
+			if person := extractor.DecodePerson(node); person != nil {
+				for _, u := range person.SameAs {
+					profiles = appendUniqueURL(profiles, u)
+				}
+			}
+		}
+	}
+	profiles = e.appendMicrodataSameAs(profiles)
+	profiles = e.appendRelMe(profiles)
+	if og, ok := e.extracted[SyntaxOpenGraph].(*extractor.OpenGraph); ok && og.Type == "profile" {
+		profiles = appendUniqueURL(profiles, og.URL)
+	}
+
+	return profiles
+}
+
+// SocialLinks returns the deduplicated social links of the page across the syntaxes: the sameAs URLs of every JSON-LD
+// node, whatever its type, given as a string or an array, the sameAs URLs of microdata items, and the rel="me" links
+// of the HTML, in this order. Unlike SocialProfiles, the links of organizations or other entities are included.
+func (e *Extractor) SocialLinks() []string {
+	var links []string
+
+	if nodes, ok := e.extracted[SyntaxJSONLD].([]map[string]any); ok {
+		for _, node := range jsonLDGraphNodes(nodes) {
+			for _, u := range jsonLDSameAs(node["sameAs"]) {
+				links = appendUniqueURL(links, u)
+			}
+		}
+	}
+	links = e.appendMicrodataSameAs(links)
+	links = e.appendRelMe(links)
+
+	return links
+}
+
+// appendMicrodataSameAs appends the sameAs URLs of the extracted microdata items to urls, skipping duplicates.
+func (e *Extractor) appendMicrodataSameAs(urls []string) []string {
+	if items, ok := e.extracted[SyntaxMicrodata].([]extractor.MicrodataItem); ok {
+		for _, item := range items {
+			for _, value := range item.Values("sameAs") {
+				if u, ok := value.(string); ok {
+					urls = appendUniqueURL(urls, u)
+				}
+			}
+		}
+	}
+
+	return urls
+}
+
+// appendRelMe appends the rel="me" links of the HTML to urls, skipping duplicates.
+func (e *Extractor) appendRelMe(urls []string) []string {
+	for _, link := range e.Links() {
+		if link.HasRel("me") {
+			urls = appendUniqueURL(urls, link.Href)
+		}
+	}
+
+	return urls
+}
+
+// jsonLDSameAs returns the URLs of a JSON-LD sameAs value, a string or an array of strings.
+func jsonLDSameAs(v any) []string {
+	switch value := v.(type) {
+	case string:
+		return []string{value}
+	case []any:
+		var urls []string
+		for _, item := range value {
+			if u, ok := item.(string); ok {
+				urls = append(urls, u)
+			}
+		}
+		return urls
+	}
+
+	return nil
+}
+
+// appendUniqueURL appends the trimmed URL u to urls unless it is empty or already listed.
+func appendUniqueURL(urls []string, u string) []string {
+	if u = strings.TrimSpace(u); u != "" && !contains(urls, u) {
+		urls = append(urls, u)
+	}
+
+	return urls
+}
@@ -0,0 +1,148 @@
+package extract
+
+import (
+	"fmt"
+	extract "github.com/aafeher/go-microdata-extract/extractors"
+	"reflect"
+	"testing"
+)
+
+func TestExtractor_ByType(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	e, err := New().Extract(fmt.Sprintf("%s/test-43-mixed-product.html", server.URL), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	organization := map[string]any{
+		"@context": "https://schema.org",
+		"@type":    []any{"Organization", "Brand"},
+		"name":     "Panasonic",
+	}
+	want := map[string][]any{
+		"Product": {
+			map[string]any{
+				"@context": "https://schema.org",
+				"@type":    "Product",
+				"name":     "Panasonic White 60L Refrigerator",
+			},
+			extract.MicrodataItem{
+				Type:  "http://schema.org/Product",
+				Types: []string{"http://schema.org/Product"},
+				Properties: map[string]any{
+					"name": "Panasonic White 60L Refrigerator",
+				},
+				PropertyOrder: []string{"name"},
+			},
+		},
+		"Organization": {organization},
+		"Brand":        {organization},
+	}
+
+	if got := e.ByType(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestExtractor_ByType_passthroughGraph(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	e, err := New().SetJSONLDPassthrough(true).Extract(fmt.Sprintf("%s/test-66-ldjson-graph.html", server.URL), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	byType := e.ByType()
+	for _, typ := range []string{"Organization", "WebSite", "WebPage", "Thing"} {
+		want := e.JSONLDByType(typ)
+		if len(want) != 1 {
+			t.Fatalf("expected one %s node in the @graph, got %v", typ, want)
+		}
+		if got := byType[typ]; len(got) != 1 || !reflect.DeepEqual(got[0], want[0]) {
+			t.Errorf("expected the %s node %v, got %v", typ, want[0], got)
+		}
+	}
+}
+
+func TestExtractor_ByType_empty(t *testing.T) {
+	e := New()
+	if got := e.ByType(); len(got) != 0 {
+		t.Errorf("expected empty map, got %v", got)
+	}
+}
+
+func TestExtractor_SocialProfiles(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	t.Run("JSON-LD Person", func(t *testing.T) {
+		e, err := New().Extract(fmt.Sprintf("%s/test-29-ldjson-object.html", server.URL), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []string{
+			"https://www.facebook.com/",
+			"https://www.linkedin.com/",
+			"http://twitter.com/",
+			"http://instagram.com/",
+			"https://plus.google.com/",
+		}
+		if got := e.SocialProfiles(); !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("across syntaxes", func(t *testing.T) {
+		content := `<html><head>
+<meta property="og:type" content="profile">
+<meta property="og:url" content="https://example.com/jane">
+<link rel="me" href="https://mastodon.example/@jane">
+<link rel="me" href="https://github.com/jane">
+<script type="application/ld+json">{"@type": "Person", "name": "Jane Doe", "sameAs": "https://github.com/jane"}</script>
+</head><body>
+<div itemscope itemtype="https://schema.org/Person"><meta itemprop="sameAs" content="https://www.linkedin.com/in/jane"></div>
+</body></html>`
+		e, err := New().Extract("https://example.com/jane", &content)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []string{
+			"https://github.com/jane",
+			"https://www.linkedin.com/in/jane",
+			"https://mastodon.example/@jane",
+			"https://example.com/jane",
+		}
+		if got := e.SocialProfiles(); !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+}
+
+func TestExtractor_SocialLinks(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	e, err := New().Extract(fmt.Sprintf("%s/test-89-social-links.html", server.URL), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"https://www.linkedin.com/company/acme",
+		"https://github.com/acme",
+		"https://x.com/acme",
+		"https://mastodon.example/@acme",
+	}
+	if got := e.SocialLinks(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	wantProfiles := []string{"https://mastodon.example/@acme", "https://github.com/acme"}
+	if got := e.SocialProfiles(); !reflect.DeepEqual(got, wantProfiles) {
+		t.Errorf("expected only the rel=\"me\" links as profiles, %v, got %v", wantProfiles, got)
+	}
+}
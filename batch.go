@@ -0,0 +1,130 @@
+package extract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Result represents the outcome of extracting a single URL with ExtractStream.
+type Result struct {
+	URL       string
+	Extracted map[Syntax]any
+	Errs      []error
+}
+
+// ExtractBatch extracts metadata from the given URLs concurrently, fetching at most concurrency of them at a time.
+// Each URL is extracted by its own Extractor sharing the configuration of e, which is left unmodified, and the
+// agents set with SetUserAgents rotate across the batch. A failed URL does not abort the batch, its error is recorded
+// in its own Extractor, see GetErrors. Returns the Extractors by URL, or an error if concurrency is less than 1.
+func (e *Extractor) ExtractBatch(urls []string, concurrency int) (map[string]*Extractor, error) {
+	if concurrency < 1 {
+		return nil, fmt.Errorf("invalid concurrency %d", concurrency)
+	}
+
+	// the extractors are created up front, so the map is only read by the goroutines
+	results := make(map[string]*Extractor, len(urls))
+	for _, url := range urls {
+		if _, ok := results[url]; ok {
+			continue
+		}
+		results[url] = e.batchExtractor(uint32(len(results)))
+	}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+	for url, be := range results {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(url string, be *Extractor) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			_, _ = be.Extract(url, nil)
+		}(url, be)
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// ExtractStream extracts metadata from the URLs received on urls with the number of workers set with
+// SetStreamWorkers, sending a *Result on results as soon as each URL is extracted. Each URL is extracted by its own
+// Extractor sharing the configuration of e, which is left unmodified, like in ExtractBatch, and a failed URL does not
+// stop the stream, its errors are in its Result. The workers block while results is full, so a slow reader holds back
+// the fetches. ExtractStream returns once urls is closed and drained or ctx is done, closing results; the fetches in
+// progress are cancelled with ctx and their results are dropped.
+func (e *Extractor) ExtractStream(ctx context.Context, urls <-chan string, results chan<- *Result) {
+	defer close(results)
+
+	workers := e.cfg.streamWorkers
+	if workers < 1 {
+		workers = defaultStreamWorkers
+	}
+
+	// the agents rotate across the stream, without touching the fetches counted by e
+	var fetches uint32
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				var url string
+				var ok bool
+				select {
+				case <-ctx.Done():
+					return
+				case url, ok = <-urls:
+					if !ok {
+						return
+					}
+				}
+
+				be := e.batchExtractor(atomic.AddUint32(&fetches, 1) - 1)
+				_, _ = be.ExtractContext(ctx, url, nil)
+				if ctx.Err() != nil {
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case results <- &Result{URL: url, Extracted: be.GetExtracted(), Errs: be.GetErrors()}:
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// MarshalBatch returns the extracted metadata of the results of ExtractBatch as an indented JSON object keyed by URL,
+// like GetExtractedJSON of each Extractor. The metadata of a URL whose extraction failed, like a failed fetch, is
+// replaced with an object holding the error message in its "error" field.
+func MarshalBatch(results map[string]*Extractor) (json.RawMessage, error) {
+	batch := make(map[string]any, len(results))
+	for url, e := range results {
+		if e == nil {
+			continue
+		}
+		if e.fatalErr != nil {
+			batch[url] = map[string]string{"error": e.fatalErr.Error()}
+			continue
+		}
+		batch[url] = e.extracted
+	}
+
+	return json.MarshalIndent(batch, "", "  ")
+}
+
+// batchExtractor returns a new Extractor with a copy of the configuration of e, fixed to the User-Agent of the nth
+// URL of the batch.
+func (e *Extractor) batchExtractor(n uint32) *Extractor {
+	be := e.Clone()
+	be.cfg.userAgent = e.userAgentAt(n)
+	be.cfg.userAgents = nil
+
+	return be
+}
@@ -0,0 +1,215 @@
+package extract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestExtractor_ExtractBatch(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	ok1 := fmt.Sprintf("%s/test-01-opengraph-minimal.html", server.URL)
+	ok2 := fmt.Sprintf("%s/test-33-w3cmicrodata-simple.html", server.URL)
+	notFound := fmt.Sprintf("%s/404", server.URL)
+
+	e := New()
+	results, err := e.ExtractBatch([]string{ok1, ok2, notFound, ok1}, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if _, ok := results[ok1].GetExtracted()[SyntaxOpenGraph]; !ok || len(results[ok1].errs) > 0 {
+		t.Errorf("expected Open Graph metadata of %s without errors, got %v", ok1, results[ok1].errs)
+	}
+	if _, ok := results[ok2].GetExtracted()[SyntaxMicrodata]; !ok || len(results[ok2].errs) > 0 {
+		t.Errorf("expected microdata of %s without errors, got %v", ok2, results[ok2].errs)
+	}
+	if len(results[notFound].errs) == 0 {
+		t.Errorf("expected an error of %s", notFound)
+	}
+	if len(e.errs) > 0 || e.url != "" {
+		t.Errorf("expected the batch Extractor to be unmodified, got %v, %q", e.errs, e.url)
+	}
+}
+
+func TestExtractor_ExtractBatch_concurrency(t *testing.T) {
+	var mu sync.Mutex
+	var running, maxRunning int
+	var agents []string
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		agents = append(agents, r.Header.Get("User-Agent"))
+		mu.Unlock()
+
+		<-release
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		_, _ = fmt.Fprintln(w, "<html></html>")
+	}))
+	defer server.Close()
+
+	var urls []string
+	for i := 0; i < 6; i++ {
+		urls = append(urls, fmt.Sprintf("%s/page-%d", server.URL, i))
+	}
+
+	e := New().SetUserAgents([]string{"agent-a", "agent-b"})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = e.ExtractBatch(urls, 2)
+	}()
+	for range urls {
+		release <- struct{}{}
+	}
+	<-done
+
+	if maxRunning > 2 {
+		t.Errorf("expected at most 2 concurrent fetches, got %d", maxRunning)
+	}
+	sort.Strings(agents)
+	want := []string{"agent-a", "agent-a", "agent-a", "agent-b", "agent-b", "agent-b"}
+	if fmt.Sprint(agents) != fmt.Sprint(want) {
+		t.Errorf("expected agents %v, got %v", want, agents)
+	}
+	if e.fetches != 0 {
+		t.Errorf("expected the batch Extractor to be unmodified, got %d fetches", e.fetches)
+	}
+}
+
+func TestExtractor_ExtractBatch_invalidConcurrency(t *testing.T) {
+	if _, err := New().ExtractBatch([]string{"https://example.com/"}, 0); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestExtractor_ExtractStream(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	ok1 := fmt.Sprintf("%s/test-01-opengraph-minimal.html", server.URL)
+	ok2 := fmt.Sprintf("%s/test-33-w3cmicrodata-simple.html", server.URL)
+	notFound := fmt.Sprintf("%s/404", server.URL)
+
+	urls := make(chan string)
+	results := make(chan *Result)
+	e := New().SetStreamWorkers(2)
+	go e.ExtractStream(context.Background(), urls, results)
+	go func() {
+		for _, url := range []string{ok1, ok2, notFound} {
+			urls <- url
+		}
+		close(urls)
+	}()
+
+	got := make(map[string]*Result)
+	for result := range results {
+		got[result.URL] = result
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(got))
+	}
+	if _, ok := got[ok1].Extracted[SyntaxOpenGraph]; !ok || len(got[ok1].Errs) > 0 {
+		t.Errorf("expected Open Graph metadata of %s without errors, got %v", ok1, got[ok1].Errs)
+	}
+	if _, ok := got[ok2].Extracted[SyntaxMicrodata]; !ok || len(got[ok2].Errs) > 0 {
+		t.Errorf("expected microdata of %s without errors, got %v", ok2, got[ok2].Errs)
+	}
+	if len(got[notFound].Errs) == 0 {
+		t.Errorf("expected an error of %s", notFound)
+	}
+	if len(e.errs) > 0 || e.url != "" {
+		t.Errorf("expected the stream Extractor to be unmodified, got %v, %q", e.errs, e.url)
+	}
+}
+
+func TestExtractor_ExtractStream_cancel(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	urls := make(chan string)
+	results := make(chan *Result)
+	done := make(chan struct{})
+	go func() {
+		New().ExtractStream(ctx, urls, results)
+		close(done)
+	}()
+
+	urls <- fmt.Sprintf("%s/test-01-opengraph-minimal.html", server.URL)
+	if result := <-results; result == nil || len(result.Errs) > 0 {
+		t.Fatalf("expected a result without errors, got %+v", result)
+	}
+
+	// urls is left open, the stream has to stop with the context
+	cancel()
+	<-done
+	if _, ok := <-results; ok {
+		t.Errorf("expected results to be closed")
+	}
+}
+
+func TestExtractor_SetStreamWorkers(t *testing.T) {
+	e := New()
+	if e.cfg.streamWorkers != defaultStreamWorkers {
+		t.Errorf("expected %d workers by default, got %d", defaultStreamWorkers, e.cfg.streamWorkers)
+	}
+	if e.SetStreamWorkers(8).cfg.streamWorkers != 8 {
+		t.Errorf("expected 8 workers, got %d", e.cfg.streamWorkers)
+	}
+	if e.SetStreamWorkers(0).cfg.streamWorkers != 8 {
+		t.Errorf("expected 0 workers to be ignored, got %d", e.cfg.streamWorkers)
+	}
+}
+
+func TestMarshalBatch(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	ok := fmt.Sprintf("%s/test-01-opengraph-minimal.html", server.URL)
+	notFound := fmt.Sprintf("%s/404", server.URL)
+
+	results, err := New().SetSyntaxes([]Syntax{SyntaxOpenGraph}).ExtractBatch([]string{ok, notFound}, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	raw, err := MarshalBatch(results)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got map[string]map[string]any
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("expected a JSON object, got %s: %v", raw, err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 URLs, got %s", raw)
+	}
+	if og, found := got[ok][string(SyntaxOpenGraph)].(map[string]any); !found || og["og:title"] == nil {
+		t.Errorf("expected the Open Graph metadata of %s, got %v", ok, got[ok])
+	}
+	if want := (&HTTPStatusError{StatusCode: 404, URL: notFound}).Error(); got[notFound]["error"] != want {
+		t.Errorf("expected the error %q of %s, got %v", want, notFound, got[notFound])
+	}
+
+	raw, err = MarshalBatch(nil)
+	if err != nil || string(raw) != "{}" {
+		t.Errorf("expected an empty object, got %s, %v", raw, err)
+	}
+}
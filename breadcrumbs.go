@@ -0,0 +1,205 @@
+package extract
+
+import (
+	extractor "github.com/aafeher/go-microdata-extract/extractors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Breadcrumb represents an element of a schema.org BreadcrumbList.
+type Breadcrumb struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// breadcrumbElement is a Breadcrumb with the position declared for it in the list.
+type breadcrumbElement struct {
+	Breadcrumb
+	position int
+}
+
+// Breadcrumbs returns the elements of the first BreadcrumbList of the page, ordered by their position. JSON-LD is
+// looked up first, then microdata, as pages often declare the same trail in both. It returns nil if there is none.
+func (e *Extractor) Breadcrumbs() []Breadcrumb {
+	if nodes, ok := e.extracted[SyntaxJSONLD].([]map[string]any); ok {
+		for _, node := range jsonLDBreadcrumbLists(nodes) {
+			if breadcrumbs := jsonLDBreadcrumbs(node); len(breadcrumbs) > 0 {
+				return breadcrumbs
+			}
+		}
+	}
+	if items, ok := e.extracted[SyntaxMicrodata].([]extractor.MicrodataItem); ok {
+		for _, item := range items {
+			if !microdataHasType(item, "BreadcrumbList") {
+				continue
+			}
+			if breadcrumbs := microdataBreadcrumbs(item); len(breadcrumbs) > 0 {
+				return breadcrumbs
+			}
+		}
+	}
+
+	return nil
+}
+
+// jsonLDBreadcrumbLists returns the BreadcrumbList nodes among nodes and the members of their @graph.
+func jsonLDBreadcrumbLists(nodes []map[string]any) []map[string]any {
+	var lists []map[string]any
+	for _, node := range jsonLDGraphNodes(nodes) {
+		if contains(extractor.JSONLDTypes(node), "BreadcrumbList") {
+			lists = append(lists, node)
+		}
+	}
+
+	return lists
+}
+
+// jsonLDBreadcrumbs returns the elements of a JSON-LD BreadcrumbList, whose item is given as a URL or as an object.
+func jsonLDBreadcrumbs(list map[string]any) []Breadcrumb {
+	var elements []breadcrumbElement
+	for _, value := range asSlice(list["itemListElement"]) {
+		listItem, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		element := breadcrumbElement{
+			Breadcrumb: Breadcrumb{Name: stringValue(listItem["name"])},
+			position:   intValue(listItem["position"]),
+		}
+		switch item := listItem["item"].(type) {
+		case string:
+			element.URL = strings.TrimSpace(item)
+		case map[string]any:
+			element.URL = stringValue(item["@id"])
+			if element.URL == "" {
+				element.URL = stringValue(item["url"])
+			}
+			if element.Name == "" {
+				element.Name = stringValue(item["name"])
+			}
+		}
+		if element.Name != "" || element.URL != "" {
+			elements = append(elements, element)
+		}
+	}
+
+	return sortBreadcrumbs(elements)
+}
+
+// microdataBreadcrumbs returns the elements of a microdata BreadcrumbList, whose item is given as a URL or as an
+// item identified by its itemid or url property.
+func microdataBreadcrumbs(list extractor.MicrodataItem) []Breadcrumb {
+	var elements []breadcrumbElement
+	for _, value := range list.Values("itemListElement") {
+		listItem, ok := value.(*extractor.MicrodataItem)
+		if !ok {
+			continue
+		}
+		element := breadcrumbElement{
+			Breadcrumb: Breadcrumb{Name: firstString(listItem.Values("name"))},
+			position:   intValue(firstString(listItem.Values("position"))),
+		}
+		if items := listItem.Values("item"); len(items) > 0 {
+			switch item := items[0].(type) {
+			case string:
+				element.URL = strings.TrimSpace(item)
+			case *extractor.MicrodataItem:
+				if item.ID != nil {
+					element.URL = strings.TrimSpace(*item.ID)
+				} else {
+					element.URL = firstString(item.Values("url"))
+				}
+				if element.Name == "" {
+					element.Name = firstString(item.Values("name"))
+				}
+			}
+		}
+		if element.Name != "" || element.URL != "" {
+			elements = append(elements, element)
+		}
+	}
+
+	return sortBreadcrumbs(elements)
+}
+
+// sortBreadcrumbs orders the elements by their position, keeping the document order of equal positions.
+func sortBreadcrumbs(elements []breadcrumbElement) []Breadcrumb {
+	if len(elements) == 0 {
+		return nil
+	}
+	sort.SliceStable(elements, func(i, j int) bool {
+		return elements[i].position < elements[j].position
+	})
+
+	breadcrumbs := make([]Breadcrumb, 0, len(elements))
+	for _, element := range elements {
+		breadcrumbs = append(breadcrumbs, element.Breadcrumb)
+	}
+
+	return breadcrumbs
+}
+
+// microdataItemTypes returns the types of item, falling back to the space-separated types of its Type for items
+// built without Types.
+func microdataItemTypes(item extractor.MicrodataItem) []string {
+	if len(item.Types) > 0 {
+		return item.Types
+	}
+
+	return strings.Fields(item.Type)
+}
+
+// microdataHasType reports whether one of the types of item is t, ignoring the schema.org prefix.
+func microdataHasType(item extractor.MicrodataItem, t string) bool {
+	for _, itemType := range microdataItemTypes(item) {
+		if extractor.NormalizeSchemaType(itemType) == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// asSlice returns v as a slice, wrapping a single value. It returns nil for a nil value.
+func asSlice(v any) []any {
+	switch value := v.(type) {
+	case nil:
+		return nil
+	case []any:
+		return value
+	default:
+		return []any{value}
+	}
+}
+
+// firstString returns the first string of a list of values.
+func firstString(values []any) string {
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			return strings.TrimSpace(s)
+		}
+	}
+
+	return ""
+}
+
+// stringValue returns v trimmed if it is a string, "" otherwise.
+func stringValue(v any) string {
+	s, _ := v.(string)
+
+	return strings.TrimSpace(s)
+}
+
+// intValue returns the integer value of a JSON number or a numeric string, 0 otherwise.
+func intValue(v any) int {
+	switch value := v.(type) {
+	case float64:
+		return int(value)
+	case string:
+		i, _ := strconv.Atoi(strings.TrimSpace(value))
+		return i
+	}
+
+	return 0
+}
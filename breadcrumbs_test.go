@@ -0,0 +1,53 @@
+package extract
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestExtractor_Breadcrumbs(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		file string
+		want []Breadcrumb
+	}{
+		{
+			name: "microdata",
+			file: "test-49-w3cmicrodata-breadcrumbs.html",
+			want: []Breadcrumb{
+				{Name: "Books", URL: "https://example.com/books"},
+				{Name: "Fiction", URL: "https://example.com/books/fiction"},
+				{Name: "Hardcover", URL: "https://example.com/books/hardcover"},
+			},
+		},
+		{
+			name: "JSON-LD graph",
+			file: "test-50-ldjson-breadcrumbs.html",
+			want: []Breadcrumb{
+				{Name: "Books", URL: "https://example.com/books"},
+				{Name: "Fiction", URL: "https://example.com/books/fiction"},
+				{Name: "Hardcover"},
+			},
+		},
+		{
+			name: "no breadcrumbs",
+			file: "test-01-opengraph-minimal.html",
+			want: nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := New().Extract(fmt.Sprintf("%s/%s", server.URL, test.file), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := e.Breadcrumbs(); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
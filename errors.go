@@ -0,0 +1,156 @@
+package extract
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidUTF8 is wrapped by the error recorded when the content contains invalid UTF-8 byte sequences, which are
+// replaced with U+FFFD before parsing.
+var ErrInvalidUTF8 = errors.New("content contains invalid UTF-8")
+
+// ErrParseTimeout is wrapped by the error returned by Extract when parsing exceeds the deadline set with
+// SetParseTimeout.
+var ErrParseTimeout = errors.New("parse timeout")
+
+// ErrNoJSONLD is wrapped by the error returned by DecodeJSONLD when no JSON-LD was extracted.
+var ErrNoJSONLD = errors.New("no JSON-LD extracted")
+
+// InvalidURLError is returned by Extract when the URL to fetch the content from is empty or malformed.
+type InvalidURLError struct {
+	URL string
+	Err error
+}
+
+// Error returns the description of the invalid URL.
+func (e *InvalidURLError) Error() string {
+	return fmt.Sprintf("invalid URL %q: %v", e.URL, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *InvalidURLError) Unwrap() error {
+	return e.Err
+}
+
+// HTTPStatusError is returned by Extract when the fetched resource, after following redirects, responds with a status
+// other than 200 OK.
+type HTTPStatusError struct {
+	StatusCode int
+	// URL is the URL of the final resource, after redirects.
+	URL string
+}
+
+// Error returns the description of the HTTP status.
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("received HTTP status %d", e.StatusCode)
+}
+
+// UnsupportedContentTypeError is returned by Extract when the fetched resource, after following redirects, is not
+// a markup or text document that can be parsed, like a PDF or an image.
+type UnsupportedContentTypeError struct {
+	// URL is the URL of the final resource, after redirects.
+	URL         string
+	ContentType string
+}
+
+// Error returns the description of the unsupported content type.
+func (e *UnsupportedContentTypeError) Error() string {
+	return fmt.Sprintf("unsupported content type %q of %q", e.ContentType, e.URL)
+}
+
+// TooManyRedirectsError is returned by Extract when fetching the content exceeds the maximum number of redirects set
+// with SetMaxRedirects, like in a redirect loop.
+type TooManyRedirectsError struct {
+	URL          string
+	MaxRedirects int
+}
+
+// Error returns the description of the exceeded redirects.
+func (e *TooManyRedirectsError) Error() string {
+	return fmt.Sprintf("too many redirects fetching %q: stopped after %d", e.URL, e.MaxRedirects)
+}
+
+// UnsupportedContentEncodingError is returned by Extract when the fetched content is compressed with an unknown
+// coding, or cannot be decompressed.
+type UnsupportedContentEncodingError struct {
+	URL             string
+	ContentEncoding string
+	Err             error
+}
+
+// Error returns the description of the unsupported content encoding.
+func (e *UnsupportedContentEncodingError) Error() string {
+	return fmt.Sprintf("unsupported content encoding %q of %q: %v", e.ContentEncoding, e.URL, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *UnsupportedContentEncodingError) Unwrap() error {
+	return e.Err
+}
+
+// BodyTooLargeError is returned by Extract when the fetched body, after decompression, exceeds the maximum size set
+// with SetMaxBodyBytes.
+type BodyTooLargeError struct {
+	URL      string
+	MaxBytes int64
+}
+
+// Error returns the description of the exceeded size.
+func (e *BodyTooLargeError) Error() string {
+	return fmt.Sprintf("body too large fetching %q: exceeds the limit of %d bytes", e.URL, e.MaxBytes)
+}
+
+// isParseableContentType reports whether a resource of the given Content-Type header can be parsed. Textual, XML and
+// JSON based media types are accepted, as well as a missing or malformed header.
+func isParseableContentType(contentType string) bool {
+	if strings.TrimSpace(contentType) == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return true
+	}
+
+	return strings.HasPrefix(mediaType, "text/") ||
+		mediaType == "application/xhtml+xml" ||
+		mediaType == "application/xml" ||
+		strings.HasSuffix(mediaType, "+xml") ||
+		isJSONContentType(contentType)
+}
+
+// isJSONContentType reports whether a resource of the given Content-Type header is a JSON document, like
+// application/ld+json, rather than a markup one.
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// validateURL checks that rawURL can be fetched: it must parse and have a scheme and a host, except for data: and
+// file: URLs. Returns an *InvalidURLError otherwise.
+func validateURL(rawURL string) error {
+	if rawURL == "" {
+		return &InvalidURLError{URL: rawURL, Err: errors.New("empty URL")}
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return &InvalidURLError{URL: rawURL, Err: err}
+	}
+	if u.Scheme == "data" || u.Scheme == "file" {
+		return nil
+	}
+	if u.Scheme == "" {
+		return &InvalidURLError{URL: rawURL, Err: errors.New("missing scheme")}
+	}
+	if u.Host == "" {
+		return &InvalidURLError{URL: rawURL, Err: errors.New("missing host")}
+	}
+
+	return nil
+}
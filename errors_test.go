@@ -0,0 +1,236 @@
+package extract
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	extractor "github.com/aafeher/go-microdata-extract/extractors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestExtractor_Extract_invalidURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{
+			name: "empty URL",
+			url:  "",
+		},
+		{
+			name: "scheme-less URL",
+			url:  "example.com/page",
+		},
+		{
+			name: "space-containing URL",
+			url:  "https://exa mple.com/page",
+		},
+		{
+			name: "host-less URL",
+			url:  "https:bad_domain",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := New().Extract(test.url, nil)
+
+			var invalidURLError *InvalidURLError
+			if !errors.As(err, &invalidURLError) {
+				t.Fatalf("expected *InvalidURLError, got %v", err)
+			}
+			if invalidURLError.URL != test.url {
+				t.Errorf("expected URL %q, got %q", test.url, invalidURLError.URL)
+			}
+			if len(e.errs) != 1 || e.errs[0] != err {
+				t.Errorf("expected errs to contain the returned error, got %v", e.errs)
+			}
+		})
+	}
+}
+
+func Test_validateURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{
+			name:    "valid URL",
+			url:     "https://example.com/page",
+			wantErr: false,
+		},
+		{
+			name:    "data URL",
+			url:     "data:text/html,<title>data</title>",
+			wantErr: false,
+		},
+		{
+			name:    "file URL",
+			url:     "file:///tmp/page.html",
+			wantErr: false,
+		},
+		{
+			name:    "empty URL",
+			url:     "",
+			wantErr: true,
+		},
+		{
+			name:    "scheme-less URL",
+			url:     "//example.com/page",
+			wantErr: true,
+		},
+		{
+			name:    "malformed URL",
+			url:     "https://example.com/%zz",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := validateURL(test.url); (err != nil) != test.wantErr {
+				t.Errorf("validateURL() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestExtractor_Extract_httpStatus(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/404", server.URL)
+	_, err := New().Extract(url, nil)
+
+	var httpStatusError *HTTPStatusError
+	if !errors.As(err, &httpStatusError) {
+		t.Fatalf("expected *HTTPStatusError, got %v", err)
+	}
+	if httpStatusError.StatusCode != 404 {
+		t.Errorf("expected status code 404, got %d", httpStatusError.StatusCode)
+	}
+	if httpStatusError.URL != url {
+		t.Errorf("expected URL %q, got %q", url, httpStatusError.URL)
+	}
+	if want := "received HTTP status 404"; err.Error() != want {
+		t.Errorf("expected error %q, got %q", want, err.Error())
+	}
+}
+
+func TestExtractor_Extract_unsupportedContentType(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	_, err := New().Extract(fmt.Sprintf("%s/redirect-to-pdf", server.URL), nil)
+
+	var unsupportedContentTypeError *UnsupportedContentTypeError
+	if !errors.As(err, &unsupportedContentTypeError) {
+		t.Fatalf("expected *UnsupportedContentTypeError, got %v", err)
+	}
+	if want := fmt.Sprintf("%s/document.pdf", server.URL); unsupportedContentTypeError.URL != want {
+		t.Errorf("expected URL %q, got %q", want, unsupportedContentTypeError.URL)
+	}
+	if unsupportedContentTypeError.ContentType != "application/pdf" {
+		t.Errorf("expected content type %q, got %q", "application/pdf", unsupportedContentTypeError.ContentType)
+	}
+}
+
+func TestExtractor_Extract_bodyTooLarge(t *testing.T) {
+	content := `<html><head><meta property="og:title" content="Title"></head><body>` + strings.Repeat("x", 4096) + `</body></html>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	_, err := New().SetMaxBodyBytes(1024).Extract(server.URL, nil)
+	var tooLargeErr *BodyTooLargeError
+	if !errors.As(err, &tooLargeErr) {
+		t.Fatalf("expected *BodyTooLargeError, got %v", err)
+	}
+	if tooLargeErr.MaxBytes != 1024 {
+		t.Errorf("expected a limit of 1024 bytes, got %d", tooLargeErr.MaxBytes)
+	}
+
+	for _, maxBytes := range []int64{0, int64(len(content))} {
+		e, err := New().SetMaxBodyBytes(maxBytes).Extract(server.URL, nil)
+		if err != nil {
+			t.Fatalf("unexpected error with a limit of %d bytes: %v", maxBytes, err)
+		}
+		if e.GetExtracted()[SyntaxOpenGraph] == nil {
+			t.Errorf("expected opengraph result with a limit of %d bytes, got %v", maxBytes, e.GetExtracted())
+		}
+	}
+}
+
+func Test_isJSONContentType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{contentType: "", want: false},
+		{contentType: "text/html", want: false},
+		{contentType: "application/json", want: true},
+		{contentType: "application/ld+json; charset=utf-8", want: true},
+		{contentType: "application/geo+json", want: true},
+	}
+	for _, test := range tests {
+		t.Run(test.contentType, func(t *testing.T) {
+			if got := isJSONContentType(test.contentType); got != test.want {
+				t.Errorf("isJSONContentType(%q) = %v, want %v", test.contentType, got, test.want)
+			}
+		})
+	}
+}
+
+func Test_isParseableContentType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{contentType: "", want: true},
+		{contentType: "text/html; charset=utf-8", want: true},
+		{contentType: "text/plain", want: true},
+		{contentType: "application/xhtml+xml", want: true},
+		{contentType: "application/ld+json", want: true},
+		{contentType: "application/json; charset=utf-8", want: true},
+		{contentType: "application/pdf", want: false},
+		{contentType: "image/png", want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.contentType, func(t *testing.T) {
+			if got := isParseableContentType(test.contentType); got != test.want {
+				t.Errorf("isParseableContentType(%q) = %v, want %v", test.contentType, got, test.want)
+			}
+		})
+	}
+}
+
+func TestExtractor_Extract_invalidUTF8(t *testing.T) {
+	content := "<html><head><meta property=\"og:title\" content=\"Caf\xe9 \xff\xfe menu\"></head></html>"
+
+	e, err := New().SetSyntaxes([]Syntax{SyntaxOpenGraph}).Extract("https://example.com/", &content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(e.errs) != 1 || !errors.Is(e.errs[0], ErrInvalidUTF8) {
+		t.Errorf("expected an error wrapping ErrInvalidUTF8, got %v", e.errs)
+	}
+
+	og, ok := e.GetExtracted()[SyntaxOpenGraph].(*extractor.OpenGraph)
+	if !ok {
+		t.Fatalf("expected *extractor.OpenGraph, got %T", e.GetExtracted()[SyntaxOpenGraph])
+	}
+	if want := "Caf\uFFFD \uFFFD menu"; og.Title != want {
+		t.Errorf("expected title %q, got %q", want, og.Title)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(e.GetExtractedJSON(), &decoded); err != nil {
+		t.Fatalf("unexpected JSON error: %v", err)
+	}
+	if len(e.errs) != 1 {
+		t.Errorf("expected no serialization error, got %v", e.errs)
+	}
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"fmt"
 	"github.com/aafeher/go-microdata-extract"
-	extractor "github.com/aafeher/go-microdata-extract/extractors"
 	"log"
 )
 
@@ -19,9 +18,10 @@ func main() {
 	extracted := em.GetExtracted()
 	fmt.Printf("Extracted data: %v\n", extracted)
 
-	extractedOG := extracted[extract.SyntaxOpenGraph].(*extractor.OpenGraph)
+	extractedOG := em.OpenGraph()
 	fmt.Printf("Extracted OG data: %v\n", extractedOG)
 
-	extractedOGTitle := extracted[extract.SyntaxOpenGraph].(*extractor.OpenGraph).Title
-	fmt.Printf("Extracted OG Title: %v\n", extractedOGTitle)
+	if extractedOG != nil {
+		fmt.Printf("Extracted OG Title: %v\n", extractedOG.Title)
+	}
 }
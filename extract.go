@@ -2,30 +2,72 @@ package extract
 
 import (
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	extractor "github.com/aafeher/go-microdata-extract/extractors"
+	"github.com/andybalholm/brotli"
+	"golang.org/x/net/html/charset"
 	"io"
+	"mime"
 	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 )
 
 type (
 	// Extractor is a struct used for extracting metadata from web content or a provided URL. It utilizes various processors.
+	// An Extractor holds the state of the last extracted page, so it must not be used by concurrent goroutines: use a
+	// Clone per goroutine, like per request of a service, or ExtractBatch.
 	Extractor struct {
-		cfg       config
-		url       string
-		content   string
-		extracted map[Syntax]any
-		errs      []error
+		cfg         config
+		url         string
+		finalURL    string
+		content     string
+		contentType string
+		response    responseMeta
+		fetches     uint32
+		extracted   map[Syntax]any
+		errs        []error
+		// fatalErr is the error that stopped the extraction of the last page, like a failed fetch.
+		fatalErr error
 	}
 
 	// config represents configuration settings for an Extractor, including syntax options, user agent, and fetch timeout.
 	config struct {
-		syntaxes     []Syntax
-		userAgent    string
-		fetchTimeout uint8
+		syntaxes      []Syntax
+		userAgent     string
+		userAgents    []string
+		agentSelector func(userAgents []string, fetch int) string
+		accept        string
+		fetchTimeout  uint8
+		maxRedirects  int
+		maxBodyBytes  int64
+		retryAttempts int
+		retryBackoff  time.Duration
+		streamWorkers int
+		httpClient    *http.Client
+		parseTimeout  time.Duration
+		baseURL       string
+		parserOptions extractor.Options
+		mergeSocial   bool
+		fetchOEmbed   bool
+	}
+
+	// responseMeta holds the status and headers of the response the content was fetched with.
+	responseMeta struct {
+		status int
+		header http.Header
 	}
 
 	// Processor represents a data structure to hold a processor's name and function for extracting metadata.
@@ -49,32 +91,88 @@ const (
 
 	// SyntaxMicrodata is the identifier used for the W3C Microdata metadata syntax.
 	SyntaxMicrodata Syntax = "microdata"
+
+	// SyntaxHTML is the identifier used for the standard HTML metadata, like <link> elements.
+	SyntaxHTML Syntax = "html"
+
+	// SyntaxOEmbed is the identifier used for the oEmbed endpoint of the page, and its oEmbed object, see
+	// SetFetchOEmbed.
+	SyntaxOEmbed Syntax = "oembed"
+
+	// SyntaxSocial is the key of the merged Open Graph and X Cards metadata, see SetMergeSocial.
+	SyntaxSocial Syntax = "social"
 )
 
 // SYNTAXES defines an array of metadata syntax identifiers supported for parsing.
 var SYNTAXES = []Syntax{SyntaxOpenGraph, SyntaxXCards, SyntaxJSONLD, SyntaxMicrodata}
 
+// optionalSyntaxes defines the built-in syntax identifiers that are supported, but not enabled by default.
+var optionalSyntaxes = []Syntax{SyntaxHTML, SyntaxOEmbed}
+
+// defaultMaxBodyBytes is the default maximum size of the fetched body, see SetMaxBodyBytes.
+const defaultMaxBodyBytes = 10 << 20
+
+// maxRetryAfter is the longest wait requested by a Retry-After header that is honored without a fetch timeout, see
+// SetRetry.
+const maxRetryAfter = 30 * time.Second
+
+// defaultStreamWorkers is the default number of workers of ExtractStream, see SetStreamWorkers.
+const defaultStreamWorkers = 4
+
 // New creates a new instance of Extractor with default configurations and an empty map for extracted data.
-func New() *Extractor {
+// The given options are applied over the defaults.
+func New(opts ...Option) *Extractor {
 	e := &Extractor{
 		extracted: make(map[Syntax]any),
 	}
 
 	e.setConfigDefaults()
+	for _, opt := range opts {
+		opt(e)
+	}
 
 	return e
 }
 
+// Clone returns a new Extractor with the configuration of e and no extracted page, which can be used concurrently
+// with e and its other clones. The configuration of e is not modified.
+func (e *Extractor) Clone() *Extractor {
+	return &Extractor{
+		cfg:       e.cfg,
+		extracted: make(map[Syntax]any),
+	}
+}
+
+// Reset clears the state of the last extracted page, its URL, content, response, extracted metadata and errors, while
+// keeping the configuration, so the Extractor can be reused for the next page. Reset followed by Extract is safe for
+// sequential reuse, but an Extractor must not be shared by concurrent goroutines; see ExtractBatch for concurrent
+// extraction.
+func (e *Extractor) Reset() {
+	e.url = ""
+	e.finalURL = ""
+	e.content = ""
+	e.contentType = ""
+	e.response = responseMeta{}
+	e.extracted = make(map[Syntax]any)
+	e.errs = nil
+	e.fatalErr = nil
+}
+
 // setConfigDefaults initializes the Extractor with default configuration settings.
 func (e *Extractor) setConfigDefaults() {
 	e.cfg = config{
-		syntaxes:     SYNTAXES,
-		userAgent:    "go-microdata-extract (+https://github.com/aafeher/go-microdata-extract/blob/main/README.md)",
-		fetchTimeout: 3,
+		syntaxes:      SYNTAXES,
+		userAgent:     "go-microdata-extract (+https://github.com/aafeher/go-microdata-extract/blob/main/README.md)",
+		fetchTimeout:  3,
+		maxRedirects:  10,
+		maxBodyBytes:  defaultMaxBodyBytes,
+		retryAttempts: 1,
+		streamWorkers: defaultStreamWorkers,
 	}
 }
 
 // SetSyntaxes sets the syntaxes that the Extractor will use for parsing metadata. Filters out unsupported syntaxes.
+// Custom syntaxes added with RegisterSyntax are supported as well.
 // syntaxes: A slice of Syntax representing the desired syntaxes.
 // Returns the updated Extractor instance.
 func (e *Extractor) SetSyntaxes(syntaxes []Syntax) *Extractor {
@@ -84,7 +182,7 @@ func (e *Extractor) SetSyntaxes(syntaxes []Syntax) *Extractor {
 
 	syntaxesToSet := make([]Syntax, 0)
 	for _, syntax := range syntaxes {
-		if contains(SYNTAXES, syntax) {
+		if isSupportedSyntax(syntax) {
 			syntaxesToSet = append(syntaxesToSet, syntax)
 		}
 	}
@@ -102,6 +200,45 @@ func (e *Extractor) SetSyntaxes(syntaxes []Syntax) *Extractor {
 // Returns the updated Extractor instance.
 func (e *Extractor) SetUserAgent(userAgent string) *Extractor {
 	e.cfg.userAgent = userAgent
+	e.cfg.userAgents = nil
+
+	return e
+}
+
+// SetUserAgents sets several User-Agent headers that the Extractor cycles through, one per fetch, for crawls where a
+// single agent gets blocked. They are picked round-robin, in order, unless a selector is set with
+// SetUserAgentSelector. Empty agents are ignored, and if none is left the rotation is turned off.
+// SetUserAgent replaces the rotation with a single agent.
+// userAgents: A slice of strings representing the User-Agents to rotate.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetUserAgents(userAgents []string) *Extractor {
+	e.cfg.userAgents = nil
+	for _, userAgent := range userAgents {
+		if userAgent != "" {
+			e.cfg.userAgents = append(e.cfg.userAgents, userAgent)
+		}
+	}
+
+	return e
+}
+
+// SetUserAgentSelector sets the function picking the User-Agent of each fetch among the agents set with
+// SetUserAgents, given the 0-based number of the fetch, like a random pick or a fixed sequence under test. It is
+// called concurrently by ExtractStream. A nil selector restores the default round-robin selection.
+// selector: A function returning the User-Agent of the fetch.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetUserAgentSelector(selector func(userAgents []string, fetch int) string) *Extractor {
+	e.cfg.agentSelector = selector
+
+	return e
+}
+
+// SetAcceptHeader sets the Accept header of the requests fetching the content, for content negotiation with the
+// endpoints serving the structured data as JSON-LD, like "application/ld+json". The header is not sent by default.
+// accept: A string representing the Accept header, or "" not to send it.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetAcceptHeader(accept string) *Extractor {
+	e.cfg.accept = accept
 
 	return e
 }
@@ -115,28 +252,370 @@ func (e *Extractor) SetFetchTimeout(fetchTimeout uint8) *Extractor {
 	return e
 }
 
+// SetMaxRedirects sets the maximum number of redirects followed when fetching the content. Defaults to 10; 0 disables
+// following redirects. Exceeding it makes Extract return a *TooManyRedirectsError.
+// maxRedirects: An int representing the maximum number of redirects.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetMaxRedirects(maxRedirects int) *Extractor {
+	if maxRedirects < 0 {
+		maxRedirects = 0
+	}
+	e.cfg.maxRedirects = maxRedirects
+
+	return e
+}
+
+// SetMaxBodyBytes sets the maximum size of the fetched body, after decompression. Defaults to 10 MiB; 0 means
+// unlimited. Exceeding it makes Extract return a *BodyTooLargeError instead of parsing a truncated body.
+// maxBytes: An int64 representing the maximum size in bytes.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetMaxBodyBytes(maxBytes int64) *Extractor {
+	if maxBytes < 0 {
+		maxBytes = 0
+	}
+	e.cfg.maxBodyBytes = maxBytes
+
+	return e
+}
+
+// SetRetry sets how many times a fetch is attempted in total when it fails with a network error or a 5xx or 429
+// response, waiting backoff before the second attempt and doubling it before each next one, or the delay of the
+// Retry-After header of the response when present, at most the fetch timeout, or 30 seconds without one. Other
+// responses are not retried. Pending retries are cancelled with the context of ExtractContext. Defaults to a single
+// attempt.
+// attempts: An int representing the total number of attempts.
+// backoff: A time.Duration representing the wait before the first retry.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetRetry(attempts int, backoff time.Duration) *Extractor {
+	if attempts < 1 {
+		attempts = 1
+	}
+	if backoff < 0 {
+		backoff = 0
+	}
+	e.cfg.retryAttempts = attempts
+	e.cfg.retryBackoff = backoff
+
+	return e
+}
+
+// SetStreamWorkers sets the number of workers ExtractStream fetches and extracts the URLs with concurrently. Defaults
+// to 4; values less than 1 are ignored.
+// workers: An int representing the number of workers.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetStreamWorkers(workers int) *Extractor {
+	if workers < 1 {
+		return e
+	}
+	e.cfg.streamWorkers = workers
+
+	return e
+}
+
+// SetBaseURL sets the URL that ExtractHTML resolves the relative URLs of the content against. Empty by default,
+// leaving them unresolved.
+// baseURL: A string representing the base URL.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetBaseURL(baseURL string) *Extractor {
+	e.cfg.baseURL = baseURL
+
+	return e
+}
+
+// SetParseTimeout sets the deadline of parsing the content, independent of its size, against pathological documents.
+// When it is exceeded, Extract returns the results of the parsers that finished in time, together with an error
+// wrapping ErrParseTimeout. 0 means no deadline, which is the default.
+// parseTimeout: A time.Duration value representing the parse deadline.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetParseTimeout(parseTimeout time.Duration) *Extractor {
+	e.cfg.parseTimeout = parseTimeout
+
+	return e
+}
+
+// SetJSONLDPropagateContext sets whether the @context of the first node of a JSON-LD array is propagated to the
+// nodes of the array that have no @context of their own, as many CMSs emit only one. Disabled by default.
+// propagate: A bool value to enable or disable the propagation.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetJSONLDPropagateContext(propagate bool) *Extractor {
+	e.cfg.parserOptions.JSONLDPropagateContext = propagate
+
+	return e
+}
+
+// SetJSONLDExpand sets whether the keys and the types of the JSON-LD nodes are expanded to full IRIs against their
+// @context, like "author" to "https://schema.org/author", for the common string and object forms of the context.
+// Remote contexts are not fetched, a string context is taken as the vocabulary. The @context is removed from the
+// expanded nodes. Disabled by default.
+// expand: A bool value to enable or disable the expansion.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetJSONLDExpand(expand bool) *Extractor {
+	e.cfg.parserOptions.JSONLDExpand = expand
+
+	return e
+}
+
+// SetJSONLDNormalizeType sets whether the @type arrays of a single type of the JSON-LD nodes, nested ones included,
+// are collapsed into a string, like ["Product"] into "Product", so the type can be read as a string, while the other
+// @type arrays are kept sorted for determinism. Disabled by default.
+// normalize: A bool value to enable or disable the normalization.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetJSONLDNormalizeType(normalize bool) *Extractor {
+	e.cfg.parserOptions.JSONLDNormalizeType = normalize
+
+	return e
+}
+
+// SetJSONLDFlattenGraphs sets whether the JSON-LD nodes holding only a @graph array are replaced with the objects of
+// the array. Disable it to keep the @graph containers while applying the other normalization steps. Enabled by
+// default.
+// flatten: A bool value to enable or disable the flattening.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetJSONLDFlattenGraphs(flatten bool) *Extractor {
+	e.cfg.parserOptions.JSONLDKeepGraphs = !flatten
+
+	return e
+}
+
+// SetJSONLDPassthrough sets whether the JSON-LD nodes are returned exactly as decoded, disabling every normalization
+// step, like the context propagation or the flattening of @graph arrays, regardless of its own setting. Disabled by
+// default.
+// passthrough: A bool value to enable or disable the passthrough.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetJSONLDPassthrough(passthrough bool) *Extractor {
+	e.cfg.parserOptions.JSONLDPassthrough = passthrough
+
+	return e
+}
+
+// SetMaxJSONLDBlockBytes sets the maximum size of a JSON-LD script block in bytes. Larger blocks are skipped and an
+// error wrapping extractor.ErrJSONLDBlockTooLarge is recorded instead. 0 means unlimited, which is the default.
+// maxBytes: An int value representing the maximum block size in bytes.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetMaxJSONLDBlockBytes(maxBytes int) *Extractor {
+	e.cfg.parserOptions.JSONLDMaxBlockBytes = maxBytes
+
+	return e
+}
+
+// SetMaxJSONLDBlocks sets the maximum number of JSON-LD script blocks parsed, for pages that inject many
+// near-duplicate blocks. The following blocks are ignored. 0 means unlimited, which is the default.
+// maxBlocks: An int value representing the maximum number of blocks.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetMaxJSONLDBlocks(maxBlocks int) *Extractor {
+	e.cfg.parserOptions.JSONLDMaxBlocks = maxBlocks
+
+	return e
+}
+
+// SetPreferContentAttribute sets whether the value of a microdata property is read from the content attribute of any
+// element that has one, not only of meta elements, before falling back to its text. Disabled by default.
+// prefer: A bool value to enable or disable reading the content attribute.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetPreferContentAttribute(prefer bool) *Extractor {
+	e.cfg.parserOptions.MicrodataPreferContent = prefer
+
+	return e
+}
+
+// SetStrict sets whether the anomalies the parsers otherwise swallow are recorded as errors naming the property and
+// the offending value: the invalid values, like an og:image:width or an article:published_time that cannot be
+// parsed and is left as the zero value, the unknown properties of the Open Graph namespaces, and the missing og:type,
+// og:title or og:url. The values are kept. This enables SetStrictOpenGraph. Disabled by default.
+// strict: A bool value to enable or disable the strict mode.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetStrict(strict bool) *Extractor {
+	return e.SetStrictOpenGraph(strict)
+}
+
+// SetStrictOpenGraph sets whether the Open Graph values are validated against the protocol, like og:determiner or
+// the integer og:image:width, recording an error wrapping extractor.ErrInvalidOpenGraphValue for each invalid one, an
+// error wrapping extractor.ErrUnknownOpenGraphProperty for each unknown property of its namespaces and an error
+// wrapping extractor.ErrMissingOpenGraphProperty for each missing og:type, og:title or og:url. The values are kept.
+// Disabled by default.
+// strict: A bool value to enable or disable the validation.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetStrictOpenGraph(strict bool) *Extractor {
+	e.cfg.parserOptions.OpenGraphStrict = strict
+
+	return e
+}
+
+// SetDedupeMedia sets whether the Open Graph images, videos and audios with the same URL, like an og:image repeated
+// for different crawlers, are collapsed into their first occurrence, filling its empty structured properties, like
+// og:image:width, from the later ones. Applies to the X Cards filled from Open Graph as well. Disabled by default.
+// dedupe: A bool value to enable or disable collapsing the duplicates.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetDedupeMedia(dedupe bool) *Extractor {
+	e.cfg.parserOptions.OpenGraphDedupeMedia = dedupe
+
+	return e
+}
+
+// SetXCardsMergeOpenGraph sets whether the missing X Cards fields are filled from the Open Graph metadata. Disable it
+// to get only the fields declared by twitter: meta tags, like when auditing the tags of a page. Enabled by default.
+// merge: A bool value to enable or disable the filling.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetXCardsMergeOpenGraph(merge bool) *Extractor {
+	e.cfg.parserOptions.XCardsSkipOpenGraph = !merge
+
+	return e
+}
+
+// SetMergeSocial sets whether the basic Open Graph and X Cards properties and media URLs are merged into a single
+// Social object stored under SyntaxSocial. Identical values are kept once, with the syntaxes that declared them. The
+// metadata not merged, like the article properties, stays under the opengraph and xcards keys. Disabled by default.
+// merge: A bool value to enable or disable the merging.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetMergeSocial(merge bool) *Extractor {
+	e.cfg.mergeSocial = merge
+
+	return e
+}
+
+// SetFetchOEmbed sets whether the oEmbed object is fetched from the endpoint discovered when SyntaxOEmbed is
+// extracted, with the same HTTP settings as the page. Otherwise only the endpoint URL is extracted, without a second
+// request. Disabled by default.
+// fetch: A bool value to enable or disable fetching the oEmbed object.
+// Returns the updated Extractor instance.
+func (e *Extractor) SetFetchOEmbed(fetch bool) *Extractor {
+	e.cfg.fetchOEmbed = fetch
+
+	return e
+}
+
 // Extract retrieves metadata from the specified URL or provided content and processes it using various parsers.
 // url: The URL to extract metadata from.
 // urlContent: Optional pointer to a string containing HTML content. If nil, the content at the URL will be fetched.
+// If the content has to be fetched and the URL is empty or malformed, an *InvalidURLError is returned.
+// Content in another charset is transcoded to UTF-8 before parsing. The charset of the Content-Type response header
+// is honored first, then the one declared by a <meta> element if the content is not valid UTF-8.
+// The remaining invalid UTF-8 byte sequences are replaced with U+FFFD, recording an error wrapping ErrInvalidUTF8,
+// so the extracted values always serialize to JSON.
 func (e *Extractor) Extract(url string, urlContent *string) (*Extractor, error) {
+	return e.ExtractContext(context.Background(), url, urlContent)
+}
+
+// ExtractHTML extracts metadata from an HTML string without a page URL, like a snippet. The relative URLs of the
+// content are resolved against the base URL set with SetBaseURL, or left unresolved if there is none.
+func (e *Extractor) ExtractHTML(content string) (*Extractor, error) {
+	return e.Extract(e.cfg.baseURL, &content)
+}
+
+// ExtractFromReader extracts metadata from the HTML content read from r, like a stream, reading it once. The relative
+// URLs of the content are resolved against baseURL, or left unresolved if it is empty.
+func (e *Extractor) ExtractFromReader(baseURL string, r io.Reader) (*Extractor, error) {
+	var content strings.Builder
+
+	if _, err := io.Copy(&content, r); err != nil {
+		e.url = baseURL
+		e.errs = append(e.errs, err)
+		e.fatalErr = err
+		return e, err
+	}
+	urlContent := content.String()
+
+	return e.Extract(baseURL, &urlContent)
+}
+
+// ExtractFromBytes extracts metadata from the HTML content b, like a cached page. The relative URLs of the content
+// are resolved against baseURL, or left unresolved if it is empty.
+func (e *Extractor) ExtractFromBytes(baseURL string, b []byte) (*Extractor, error) {
+	urlContent := string(b)
+
+	return e.Extract(baseURL, &urlContent)
+}
+
+// ExtractFromFile extracts metadata from the HTML file at path, decompressed if the path ends in .gz, like a cached
+// page. The relative URLs of the content are resolved against the base URL set with SetBaseURL, or left unresolved
+// if there is none.
+func (e *Extractor) ExtractFromFile(path string) (*Extractor, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		e.url = e.cfg.baseURL
+		e.errs = append(e.errs, err)
+		e.fatalErr = err
+		return e, err
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+
+	var r io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			e.url = e.cfg.baseURL
+			e.errs = append(e.errs, err)
+			e.fatalErr = err
+			return e, err
+		}
+		r = gzipReader
+	}
+
+	return e.ExtractFromReader(e.cfg.baseURL, r)
+}
+
+// ExtractContext retrieves metadata like Extract, under the given context. The fetch is cancelled with the context,
+// and when the context is done while parsing, the results of the parsers that finished are kept. In both cases,
+// ctx.Err() is returned.
+func (e *Extractor) ExtractContext(ctx context.Context, url string, urlContent *string) (*Extractor, error) {
 	var err error
-	var mu sync.Mutex
-	var wg sync.WaitGroup
 
 	e.url = url
-	e.content, err = e.setContent(urlContent)
+	e.finalURL = url
+	e.contentType = ""
+	e.response = responseMeta{}
+	e.fatalErr = nil
+	if urlContent == nil {
+		if err = validateURL(url); err != nil {
+			e.errs = append(e.errs, err)
+			e.fatalErr = err
+			return e, err
+		}
+	}
+	e.content, err = e.setContent(ctx, urlContent)
 	if err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
 		e.errs = append(e.errs, err)
+		e.fatalErr = err
 		return e, err
 	}
+	if !utf8.ValidString(e.content) {
+		// invalid bytes would end up in the extracted strings and be mangled when serialized to JSON
+		e.content = strings.ToValidUTF8(e.content, string(utf8.RuneError))
+		e.errs = append(e.errs, fmt.Errorf("%w: invalid byte sequences replaced with U+FFFD", ErrInvalidUTF8))
+	}
+
+	// the parsers get a snapshot of the content and the settings, as those abandoned after a parse timeout may run
+	// while the Extractor is reused
+	pageURL, content, opts := e.finalURL, e.content, e.cfg.parserOptions
+	fetchOEmbed := e.cfg.fetchOEmbed
+	xCardsOpts := opts
+	xCardsOpts.XCardsSkipOpenGraph = xCardsOpts.XCardsSkipOpenGraph || e.cfg.mergeSocial
 
 	var processors []Processor
 
+	if isJSONContentType(e.contentType) {
+		// a JSON document, like a content negotiated application/ld+json response, has no markup to tokenize
+		if contains(e.cfg.syntaxes, SyntaxJSONLD) {
+			processors = append(processors, Processor{
+				Name: SyntaxJSONLD,
+				Func: func() (any, []error) {
+					return extractor.JSONLDDocumentWithOptions(pageURL, content, opts)
+				},
+			})
+		}
+		return e, e.runProcessors(ctx, processors)
+	}
+
 	if contains(e.cfg.syntaxes, SyntaxOpenGraph) {
 		processors = append(processors, Processor{
 			Name: SyntaxOpenGraph,
 			Func: func() (any, []error) {
-				return extractor.ParseOpenGraph(e.url, e.content)
+				return extractor.ParseOpenGraphWithOptions(pageURL, content, opts)
 			},
 		})
 	}
@@ -144,7 +623,7 @@ func (e *Extractor) Extract(url string, urlContent *string) (*Extractor, error)
 		processors = append(processors, Processor{
 			Name: SyntaxXCards,
 			Func: func() (any, []error) {
-				return extractor.ParseXCards(e.url, e.content)
+				return extractor.ParseXCardsWithOptions(pageURL, content, xCardsOpts)
 			},
 		})
 	}
@@ -152,7 +631,7 @@ func (e *Extractor) Extract(url string, urlContent *string) (*Extractor, error)
 		processors = append(processors, Processor{
 			Name: SyntaxJSONLD,
 			Func: func() (any, []error) {
-				return extractor.JSONLD(e.url, e.content)
+				return extractor.JSONLDWithOptions(pageURL, content, opts)
 			},
 		})
 	}
@@ -160,11 +639,57 @@ func (e *Extractor) Extract(url string, urlContent *string) (*Extractor, error)
 		processors = append(processors, Processor{
 			Name: SyntaxMicrodata,
 			Func: func() (any, []error) {
-				return extractor.W3CMicrodata(e.url, e.content)
+				return extractor.W3CMicrodataWithOptions(pageURL, content, opts)
+			},
+		})
+	}
+	if contains(e.cfg.syntaxes, SyntaxHTML) {
+		processors = append(processors, Processor{
+			Name: SyntaxHTML,
+			Func: func() (any, []error) {
+				return extractor.ParseHTMLMeta(pageURL, content)
+			},
+		})
+	}
+	if contains(e.cfg.syntaxes, SyntaxOEmbed) {
+		processors = append(processors, Processor{
+			Name: SyntaxOEmbed,
+			Func: func() (any, []error) {
+				item, errs := extractor.ParseOEmbed(pageURL, content)
+				if oembed, ok := item.(*extractor.OEmbed); ok && fetchOEmbed {
+					data, err := e.fetchOEmbed(ctx, oembed.Endpoint)
+					if err != nil {
+						errs = append(errs, err)
+					}
+					oembed.Data = data
+				}
+				return item, errs
 			},
 		})
 	}
+	for _, syntax := range e.cfg.syntaxes {
+		if parser, ok := registeredParser(syntax); ok {
+			processors = append(processors, Processor{
+				Name: syntax,
+				Func: func() (any, []error) {
+					return parser(pageURL, content)
+				},
+			})
+		}
+	}
+
+	return e, e.runProcessors(ctx, processors)
+}
+
+// runProcessors runs the processors concurrently, recording their results and errors, until they finish or the parse
+// deadline is exceeded, then merges the social metadata if set. Returns the error of an exceeded deadline or a done
+// context.
+func (e *Extractor) runProcessors(ctx context.Context, processors []Processor) error {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
 
+	// stopped is set once the parse deadline is exceeded, the results of the parsers finishing later are discarded
+	stopped := false
 	for _, processor := range processors {
 		wg.Add(1)
 		proc := processor
@@ -174,68 +699,497 @@ func (e *Extractor) Extract(url string, urlContent *string) (*Extractor, error)
 
 			mu.Lock()
 			defer mu.Unlock()
+			if stopped {
+				return
+			}
 			e.errs = append(e.errs, errorsExtracted...)
 			e.extracted[proc.Name] = extracted
 		}(proc)
 	}
 
-	wg.Wait()
+	err := e.waitParsers(ctx, &wg, &mu, &stopped)
+	if err != nil {
+		e.errs = append(e.errs, err)
+	}
+
+	if e.cfg.mergeSocial {
+		e.mergeSocial()
+	}
+
+	return err
+}
+
+// waitParsers waits for the parsers of wg to finish, at most until the parse timeout or until ctx is done. In those
+// cases, stopped is set under mu and an error wrapping ErrParseTimeout or ctx.Err() is returned.
+func (e *Extractor) waitParsers(ctx context.Context, wg *sync.WaitGroup, mu *sync.Mutex, stopped *bool) error {
+	if e.cfg.parseTimeout <= 0 && ctx.Done() == nil {
+		wg.Wait()
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	// a nil channel blocks forever, when there is no parse timeout
+	var timeout <-chan time.Time
+	if e.cfg.parseTimeout > 0 {
+		timer := time.NewTimer(e.cfg.parseTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var err error
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timeout:
+		err = fmt.Errorf("%w: exceeded %s", ErrParseTimeout, e.cfg.parseTimeout)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	*stopped = true
 
-	return e, nil
+	return err
 }
 
-// setContent sets the content for the Extractor, fetching from URL if necessary. Returns the content or an error.
-func (e *Extractor) setContent(urlContent *string) (string, error) {
+// nextUserAgent returns the User-Agent of the next fetch, picked among the rotated agents if there are any, by the
+// selector set with SetUserAgentSelector or else round-robin.
+func (e *Extractor) nextUserAgent() string {
+	if len(e.cfg.userAgents) == 0 {
+		return e.cfg.userAgent
+	}
+
+	return e.userAgentAt(atomic.AddUint32(&e.fetches, 1) - 1)
+}
+
+// userAgentAt returns the User-Agent of the nth fetch, counted from 0, among the rotated agents if there are any.
+func (e *Extractor) userAgentAt(n uint32) string {
+	if len(e.cfg.userAgents) == 0 {
+		return e.cfg.userAgent
+	}
+	if e.cfg.agentSelector != nil {
+		return e.cfg.agentSelector(e.cfg.userAgents, int(n))
+	}
+
+	return e.cfg.userAgents[n%uint32(len(e.cfg.userAgents))]
+}
+
+// setContent sets the content for the Extractor, fetching from URL if necessary. Returns the content transcoded to
+// UTF-8 or an error.
+func (e *Extractor) setContent(ctx context.Context, urlContent *string) (string, error) {
 	if urlContent != nil {
-		return *urlContent, nil
+		return e.decodeCharset(*urlContent), nil
 	}
-	mainURLContent, err := e.fetch(e.url)
+	mainURLContent, err := e.fetch(ctx, e.url)
 
 	if err != nil {
 		return "", err
 	}
-	return string(mainURLContent), nil
+	return e.decodeCharset(string(mainURLContent)), nil
 }
 
-// fetch retrieves the content from the specified URL. Returns the fetched content as a byte slice or an error if failed.
-func (e *Extractor) fetch(url string) ([]byte, error) {
-	var body bytes.Buffer
+// decodeCharset transcodes the content to UTF-8 from the charset declared in the Content-Type response header, or if
+// there is none and the content is not valid UTF-8, from the one declared by its <meta> elements. The content is
+// returned unchanged if the charset is UTF-8, undeclared or unknown.
+func (e *Extractor) decodeCharset(content string) string {
+	label := headerCharset(e.contentType)
+	if label == "" {
+		if utf8.ValidString(content) {
+			return content
+		}
+		if hm, _ := extractor.ParseHTMLMeta(e.finalURL, content); hm != nil {
+			label = hm.(*extractor.HTMLMeta).Charset
+		}
+	}
 
-	client := &http.Client{
-		Timeout: time.Duration(e.cfg.fetchTimeout) * time.Second,
+	enc, name := charset.Lookup(label)
+	if enc == nil || name == "utf-8" {
+		return content
 	}
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	decoded, err := enc.NewDecoder().String(content)
 	if err != nil {
-		return nil, err
+		return content
 	}
 
-	req.Header.Set("User-Agent", e.cfg.userAgent)
+	return decoded
+}
 
-	response, err := client.Do(req)
+// headerCharset returns the lowercased charset parameter of a Content-Type header, or "" if there is none.
+func headerCharset(contentType string) string {
+	if _, params, err := mime.ParseMediaType(contentType); err == nil {
+		return strings.ToLower(params["charset"])
+	}
+
+	return ""
+}
+
+// fetch retrieves the content from the specified URL. Returns the fetched content as a byte slice or an error if failed.
+// The fragment of the URL is not sent, just as browsers do not send it.
+// The client set with WithHTTPClient is used if any, its own timeout and redirect policy taking precedence.
+// Redirects are followed up to the maximum set with SetMaxRedirects, recording the final URL, and if the final
+// resource is not parseable, an *UnsupportedContentTypeError is returned.
+func (e *Extractor) fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	response, err := e.get(ctx, rawURL)
 	if err != nil {
 		return nil, err
 	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(response.Body)
+
+	// the request of a redirected response records the response redirecting to it, the fragment of the given URL is
+	// kept otherwise
+	e.finalURL = rawURL
+	if response.Request.Response != nil {
+		e.finalURL = response.Request.URL.String()
+	}
+	e.response = responseMeta{status: response.StatusCode, header: response.Header}
+	e.contentType = response.Header.Get("Content-Type")
 
 	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("received HTTP status %d", response.StatusCode)
+		return nil, &HTTPStatusError{StatusCode: response.StatusCode, URL: response.Request.URL.String()}
+	}
+
+	if !isParseableContentType(e.contentType) {
+		return nil, &UnsupportedContentTypeError{URL: response.Request.URL.String(), ContentType: e.contentType}
+	}
+
+	return e.readBody(response)
+}
+
+// fetchOEmbed retrieves the oEmbed object from the endpoint URL, like fetch, but without recording the response as
+// the one of the page. Returns the decoded object or an error wrapping the reason it could not be fetched.
+func (e *Extractor) fetchOEmbed(ctx context.Context, endpoint string) (map[string]any, error) {
+	response, err := e.get(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("fetching oEmbed: %w", err)
 	}
 	defer func(Body io.ReadCloser) {
 		_ = Body.Close()
 	}(response.Body)
 
-	_, err = io.Copy(&body, response.Body)
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching oEmbed: %w", &HTTPStatusError{StatusCode: response.StatusCode, URL: response.Request.URL.String()})
+	}
+
+	body, err := e.readBody(response)
+	if err != nil {
+		return nil, fmt.Errorf("fetching oEmbed: %w", err)
+	}
+
+	var data map[string]any
+	if err = json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("decoding oEmbed: %w", err)
+	}
+
+	return data, nil
+}
+
+// get sends a GET request to the specified URL with the User-Agent and the HTTP settings of the Extractor, following
+// redirects up to the maximum set with SetMaxRedirects and retrying as set with SetRetry. The caller has to close the
+// body of the returned response.
+func (e *Extractor) get(ctx context.Context, rawURL string) (*http.Response, error) {
+	// a copy of the given client is used, so its own timeout, if set, takes precedence over the fetch timeout
+	client := &http.Client{}
+	if e.cfg.httpClient != nil {
+		*client = *e.cfg.httpClient
+	}
+	if client.Timeout == 0 {
+		client.Timeout = time.Duration(e.cfg.fetchTimeout) * time.Second
+	}
+	if client.CheckRedirect == nil {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if len(via) > e.cfg.maxRedirects {
+				return &TooManyRedirectsError{URL: rawURL, MaxRedirects: e.cfg.maxRedirects}
+			}
+			return nil
+		}
+	}
+
+	backoff := e.cfg.retryBackoff
+	for attempt := 1; ; attempt++ {
+		response, err := e.send(ctx, client, rawURL)
+		if attempt >= e.cfg.retryAttempts || !isRetryable(ctx, response, err) {
+			return response, err
+		}
+
+		wait := backoff
+		if response != nil {
+			if retryAfter, ok := parseRetryAfter(response.Header.Get("Retry-After")); ok {
+				wait = capRetryAfter(retryAfter, client.Timeout)
+			}
+			_ = response.Body.Close()
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// send sends a single GET request to the specified URL with the client.
+func (e *Extractor) send(ctx context.Context, client *http.Client, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, stripFragment(rawURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", e.nextUserAgent())
+	if e.cfg.accept != "" {
+		req.Header.Set("Accept", e.cfg.accept)
+	}
+	// set explicitly, the transport would only ask for gzip, and then decompress the body itself
+	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+
+	response, err := client.Do(req)
+	if err != nil {
+		var redirectsErr *TooManyRedirectsError
+		if errors.As(err, &redirectsErr) {
+			return nil, redirectsErr
+		}
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// isRetryable reports whether a request that ended with the response or the error is worth retrying: a network error,
+// but not a cancelled context or too many redirects, or a 5xx or 429 response.
+func isRetryable(ctx context.Context, response *http.Response, err error) bool {
+	if err != nil {
+		var redirectsErr *TooManyRedirectsError
+		return ctx.Err() == nil && !errors.As(err, &redirectsErr)
+	}
+
+	return response.StatusCode >= http.StatusInternalServerError || response.StatusCode == http.StatusTooManyRequests
+}
+
+// parseRetryAfter returns the delay of a Retry-After header, given in seconds or as an HTTP date.
+func parseRetryAfter(retryAfter string) (time.Duration, bool) {
+	retryAfter = strings.TrimSpace(retryAfter)
+	if retryAfter == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(retryAfter); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(retryAfter); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// capRetryAfter limits the wait requested by a Retry-After header to the fetch timeout, or to maxRetryAfter without
+// one, so a server asking for a day or a far-future date cannot stall the fetch.
+func capRetryAfter(wait, timeout time.Duration) time.Duration {
+	limit := timeout
+	if limit <= 0 {
+		limit = maxRetryAfter
+	}
+	if wait > limit {
+		return limit
+	}
+
+	return wait
+}
+
+// readBody reads the body of the response, decoded from its Content-Encoding, up to the maximum set with
+// SetMaxBodyBytes.
+func (e *Extractor) readBody(response *http.Response) ([]byte, error) {
+	var body bytes.Buffer
+
+	contentEncoding := response.Header.Get("Content-Encoding")
+	reader, err := decodeContent(contentEncoding, response.Body)
 	if err != nil {
+		return nil, &UnsupportedContentEncodingError{URL: response.Request.URL.String(), ContentEncoding: contentEncoding, Err: err}
+	}
+
+	if e.cfg.maxBodyBytes > 0 {
+		reader = io.LimitReader(reader, e.cfg.maxBodyBytes+1)
+	}
+	if _, err = io.Copy(&body, reader); err != nil {
 		return nil, err
 	}
+	if e.cfg.maxBodyBytes > 0 && int64(body.Len()) > e.cfg.maxBodyBytes {
+		return nil, &BodyTooLargeError{URL: response.Request.URL.String(), MaxBytes: e.cfg.maxBodyBytes}
+	}
 
 	return body.Bytes(), nil
 }
 
+// decodeContent wraps r in the decoders of the comma-separated content codings, given in the order they were applied.
+// The gzip, deflate and br codings are supported.
+func decodeContent(contentEncoding string, r io.Reader) (io.Reader, error) {
+	codings := strings.Split(contentEncoding, ",")
+	for i := len(codings) - 1; i >= 0; i-- {
+		switch coding := strings.ToLower(strings.TrimSpace(codings[i])); coding {
+		case "", "identity":
+		case "gzip", "x-gzip":
+			gzipReader, err := gzip.NewReader(r)
+			if err != nil {
+				return nil, err
+			}
+			r = gzipReader
+		case "deflate":
+			zlibReader, err := zlib.NewReader(r)
+			if err != nil {
+				return nil, err
+			}
+			r = zlibReader
+		case "br":
+			r = brotli.NewReader(r)
+		default:
+			return nil, fmt.Errorf("unknown content coding %q", coding)
+		}
+	}
+
+	return r, nil
+}
+
+// stripFragment returns rawURL without its fragment. Unparseable URLs are returned unchanged.
+func stripFragment(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	return u.String()
+}
+
+// FinalURL returns the URL of the extracted page after following redirects, which the relative URLs of the content
+// are resolved against. It is the URL given to Extract if the content was provided or not redirected.
+func (e *Extractor) FinalURL() string {
+	return e.finalURL
+}
+
+// ResponseStatus returns the HTTP status code of the response the content was fetched with, after redirects.
+// Returns 0 if the content was provided or could not be fetched.
+func (e *Extractor) ResponseStatus() int {
+	return e.response.status
+}
+
+// ResponseHeader returns the HTTP headers of the response the content was fetched with, after redirects, like ETag
+// or Last-Modified. Returns nil if the content was provided or could not be fetched.
+func (e *Extractor) ResponseHeader() http.Header {
+	return e.response.header
+}
+
 // GetExtracted returns the extracted metadata as a map by processor name from the Extractor instance.
 func (e *Extractor) GetExtracted() map[Syntax]any {
 	return e.extracted
 }
 
+// FoundSyntaxes returns the syntaxes of the extracted metadata that hold data, skipping the nil values, like an Open
+// Graph metadata not found, and the empty ones, like no microdata items. The built-in syntaxes come in the order of
+// SYNTAXES, followed by the optional ones, SyntaxSocial and the registered syntaxes.
+func (e *Extractor) FoundSyntaxes() []Syntax {
+	order := append(append(append([]Syntax{}, SYNTAXES...), optionalSyntaxes...), SyntaxSocial)
+	for _, syntax := range e.cfg.syntaxes {
+		if !contains(order, syntax) {
+			order = append(order, syntax)
+		}
+	}
+
+	var found []Syntax
+	for _, syntax := range order {
+		if hasData(e.extracted[syntax]) {
+			found = append(found, syntax)
+		}
+	}
+
+	return found
+}
+
+// hasData reports whether v is neither nil, a nil pointer, nor an empty slice or map.
+func hasData(v any) bool {
+	if v == nil {
+		return false
+	}
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return !value.IsNil()
+	case reflect.Slice, reflect.Map:
+		return value.Len() > 0
+	}
+
+	return true
+}
+
+// OpenGraph returns the extracted Open Graph metadata, without parsing the content again. Returns nil if it was not
+// extracted, or only the metadata not merged if SetMergeSocial is enabled.
+func (e *Extractor) OpenGraph() *extractor.OpenGraph {
+	og, _ := e.extracted[SyntaxOpenGraph].(*extractor.OpenGraph)
+
+	return og
+}
+
+// ValidateOpenGraph returns an error wrapping extractor.ErrMissingOpenGraphProperty for each property required by the
+// Open Graph protocol that the extracted Open Graph metadata does not declare, see extractor.OpenGraph.Validate. All
+// of them are missing if no Open Graph metadata was extracted.
+func (e *Extractor) ValidateOpenGraph() []error {
+	og := e.OpenGraph()
+	if og == nil {
+		og = extractor.NewOpenGraph()
+	}
+
+	return og.Validate()
+}
+
+// XCards returns the extracted X Cards metadata, without parsing the content again. Returns nil if it was not
+// extracted, or only the metadata not merged if SetMergeSocial is enabled.
+func (e *Extractor) XCards() *extractor.XCards {
+	xc, _ := e.extracted[SyntaxXCards].(*extractor.XCards)
+
+	return xc
+}
+
+// JSONLD returns the extracted JSON-LD nodes, without parsing the content again. Returns nil if none was extracted.
+func (e *Extractor) JSONLD() []map[string]any {
+	nodes, _ := e.extracted[SyntaxJSONLD].([]map[string]any)
+
+	return nodes
+}
+
+// Microdata returns the extracted microdata items, without parsing the content again. Returns nil if none was
+// extracted.
+func (e *Extractor) Microdata() []extractor.MicrodataItem {
+	items, _ := e.extracted[SyntaxMicrodata].([]extractor.MicrodataItem)
+
+	return items
+}
+
+// GetErrors returns a copy of the errors recorded by the Extractor: the fetch error, and the warnings of the parsers
+// that do not fail the extraction, like malformed JSON-LD blocks, microdata parse errors or invalid UTF-8 content.
+func (e *Extractor) GetErrors() []error {
+	if len(e.errs) == 0 {
+		return nil
+	}
+	errs := make([]error, len(e.errs))
+	copy(errs, e.errs)
+
+	return errs
+}
+
 // GetExtractedJSON returns the extracted metadata as a JSON-formatted byte array with indentation.
 func (e *Extractor) GetExtractedJSON() json.RawMessage {
 	extractedJSON, errJSON := json.MarshalIndent(e.extracted, "", "  ")
@@ -246,6 +1200,43 @@ func (e *Extractor) GetExtractedJSON() json.RawMessage {
 	return extractedJSON
 }
 
+// DeclaredCharset returns the lowercased charset declared for the extracted page: the charset parameter of the
+// Content-Type response header if the page was fetched, otherwise the one declared by a <meta charset> or
+// <meta http-equiv="Content-Type"> element. Returns "" if no charset is declared.
+func (e *Extractor) DeclaredCharset() string {
+	if label := headerCharset(e.contentType); label != "" {
+		return label
+	}
+	if hm := e.htmlMeta(); hm != nil {
+		return hm.Charset
+	}
+
+	return ""
+}
+
+// Links returns all <link> elements of the extracted page, with their href resolved against the page URL.
+// The HTML metadata is parsed on demand if SyntaxHTML was not among the extracted syntaxes.
+func (e *Extractor) Links() []extractor.HTMLLink {
+	if hm := e.htmlMeta(); hm != nil {
+		return hm.Links
+	}
+
+	return nil
+}
+
+// htmlMeta returns the extracted HTML metadata, parsing the content if SyntaxHTML was not extracted.
+func (e *Extractor) htmlMeta() *extractor.HTMLMeta {
+	if hm, ok := e.extracted[SyntaxHTML].(*extractor.HTMLMeta); ok {
+		return hm
+	}
+	hm, _ := extractor.ParseHTMLMeta(e.finalURL, e.content)
+	if hm == nil {
+		return nil
+	}
+
+	return hm.(*extractor.HTMLMeta)
+}
+
 // index returns the index of the first occurrence of v in s,
 // or -1 if not present.
 func index[S ~[]E, E comparable](s S, v E) int {
@@ -2,12 +2,26 @@ package extract
 
 import (
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	extract "github.com/aafeher/go-microdata-extract/extractors"
+	"github.com/andybalholm/brotli"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"testing/iotest"
 	"time"
 )
 
@@ -24,6 +38,8 @@ func TestExtractor_setConfigDefaults(t *testing.T) {
 				syntaxes:     SYNTAXES,
 				userAgent:    "go-microdata-extract (+https://github.com/aafeher/go-microdata-extract/blob/main/README.md)",
 				fetchTimeout: 3,
+				maxRedirects: 10,
+				maxBodyBytes: 10 << 20,
 			},
 		},
 	}
@@ -32,13 +48,88 @@ func TestExtractor_setConfigDefaults(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			test.e.setConfigDefaults()
 
-			if !areSyntaxSlicesEqual(test.e.cfg.syntaxes, test.want.syntaxes) || test.e.cfg.userAgent != test.want.userAgent || test.e.cfg.fetchTimeout != test.want.fetchTimeout {
+			if !areSyntaxSlicesEqual(test.e.cfg.syntaxes, test.want.syntaxes) || test.e.cfg.userAgent != test.want.userAgent || test.e.cfg.fetchTimeout != test.want.fetchTimeout || test.e.cfg.maxRedirects != test.want.maxRedirects || test.e.cfg.maxBodyBytes != test.want.maxBodyBytes {
 				t.Errorf("expected %v, got %v", test.want, test.e.cfg)
 			}
 		})
 	}
 }
 
+func TestExtractor_Clone(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	pages := []string{
+		fmt.Sprintf("%s/test-01-opengraph-minimal.html", server.URL),
+		fmt.Sprintf("%s/test-33-w3cmicrodata-simple.html", server.URL),
+	}
+
+	e := New().SetUserAgent("pooled-agent").SetMaxRedirects(3)
+	clones := make([]*Extractor, 8)
+	var wg sync.WaitGroup
+	for i := range clones {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			clone := e.Clone()
+			_, _ = clone.Extract(pages[i%len(pages)], nil)
+			clones[i] = clone
+		}(i)
+	}
+	wg.Wait()
+
+	for i, clone := range clones {
+		if clone.FinalURL() != pages[i%len(pages)] || clone.GetErrors() != nil {
+			t.Errorf("expected clone %d to hold %q without errors, got %q and %v", i, pages[i%len(pages)], clone.FinalURL(), clone.GetErrors())
+		}
+		if clone.cfg.userAgent != "pooled-agent" || clone.cfg.maxRedirects != 3 {
+			t.Errorf("expected clone %d to keep the configuration, got %+v", i, clone.cfg)
+		}
+	}
+	if clones[0].OpenGraph() == nil || clones[1].Microdata() == nil {
+		t.Errorf("expected the metadata of each page, got %v and %v", clones[0].GetExtracted(), clones[1].GetExtracted())
+	}
+	if e.url != "" || len(e.GetExtracted()) != 0 {
+		t.Errorf("expected the cloned Extractor to be unmodified, got %q and %v", e.url, e.GetExtracted())
+	}
+}
+
+func TestExtractor_Reset(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	e := New().SetUserAgent("reused-agent").SetSyntaxes([]Syntax{SyntaxOpenGraph, SyntaxMicrodata})
+	if _, err := e.Extract(fmt.Sprintf("%s/test-73-opengraph-multiple-images.html", server.URL), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.OpenGraph() == nil {
+		t.Fatalf("expected opengraph result, got %v", e.GetExtracted())
+	}
+	e.errs = append(e.errs, errors.New("previous page error"))
+
+	e.Reset()
+	if e.url != "" || e.finalURL != "" || e.content != "" || len(e.GetExtracted()) != 0 || e.GetErrors() != nil {
+		t.Fatalf("expected the page state cleared, got %+v", e)
+	}
+	if e.cfg.userAgent != "reused-agent" || !areSyntaxSlicesEqual(e.cfg.syntaxes, []Syntax{SyntaxOpenGraph, SyntaxMicrodata}) {
+		t.Errorf("expected the configuration kept, got %+v", e.cfg)
+	}
+
+	url := fmt.Sprintf("%s/test-33-w3cmicrodata-simple.html", server.URL)
+	if _, err := e.Extract(url, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if og := e.OpenGraph(); og != nil {
+		t.Errorf("expected no opengraph result of the previous page, got %+v", og)
+	}
+	if items := e.Microdata(); len(items) != 1 {
+		t.Errorf("expected the microdata item of the second page, got %v", items)
+	}
+	if e.FinalURL() != url || e.GetErrors() != nil {
+		t.Errorf("expected the state of the second page, got %q and %v", e.FinalURL(), e.GetErrors())
+	}
+}
+
 func TestExtractor_SetSyntaxes(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -70,6 +161,11 @@ func TestExtractor_SetSyntaxes(t *testing.T) {
 			syntaxes: SYNTAXES,
 			want:     SYNTAXES,
 		},
+		{
+			name:     "optional syntax list",
+			syntaxes: []Syntax{SyntaxOpenGraph, SyntaxHTML},
+			want:     []Syntax{SyntaxOpenGraph, SyntaxHTML},
+		},
 	}
 
 	for _, test := range tests {
@@ -112,6 +208,158 @@ func TestExtractor_SetUserAgent(t *testing.T) {
 	}
 }
 
+func TestExtractor_SetUserAgents(t *testing.T) {
+	tests := []struct {
+		name       string
+		userAgents []string
+		want       []string
+	}{
+		{
+			name:       "Nil User Agents",
+			userAgents: nil,
+			want:       nil,
+		},
+		{
+			name:       "Empty User Agents",
+			userAgents: []string{"", ""},
+			want:       nil,
+		},
+		{
+			name:       "Normal User Agents",
+			userAgents: []string{"Mozilla/5.0 Firefox/61.0", "", "Mozilla/5.0 Chrome/120.0"},
+			want:       []string{"Mozilla/5.0 Firefox/61.0", "Mozilla/5.0 Chrome/120.0"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := New()
+			e.SetUserAgents(test.userAgents)
+			if !reflect.DeepEqual(e.cfg.userAgents, test.want) {
+				t.Errorf("expected %q, got %q", test.want, e.cfg.userAgents)
+			}
+		})
+	}
+}
+
+func TestExtractor_SetUserAgents_rotation(t *testing.T) {
+	var mu sync.Mutex
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		got = append(got, r.UserAgent())
+		mu.Unlock()
+		_, _ = fmt.Fprintln(w, "<html></html>")
+	}))
+	defer server.Close()
+
+	e := New().SetUserAgents([]string{"agent-1", "agent-2", "agent-3"})
+	for i := 0; i < 4; i++ {
+		if _, err := e.Extract(server.URL, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	want := []string{"agent-1", "agent-2", "agent-3", "agent-1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	e.SetUserAgent("single-agent")
+	got = nil
+	if _, err := e.Extract(server.URL, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"single-agent"}) {
+		t.Errorf("expected %q, got %q", []string{"single-agent"}, got)
+	}
+}
+
+func TestExtractor_SetUserAgentSelector(t *testing.T) {
+	var mu sync.Mutex
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		got = append(got, r.UserAgent())
+		mu.Unlock()
+		_, _ = fmt.Fprintln(w, "<html></html>")
+	}))
+	defer server.Close()
+
+	var fetches []int
+	reverse := func(userAgents []string, fetch int) string {
+		fetches = append(fetches, fetch)
+		return userAgents[len(userAgents)-1-fetch%len(userAgents)]
+	}
+	e := New().SetUserAgents([]string{"agent-1", "agent-2", "agent-3"}).SetUserAgentSelector(reverse)
+	for i := 0; i < 4; i++ {
+		if _, err := e.Extract(server.URL, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if want := []string{"agent-3", "agent-2", "agent-1", "agent-3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if want := []int{0, 1, 2, 3}; !reflect.DeepEqual(fetches, want) {
+		t.Errorf("expected the selector to be called with fetches %v, got %v", want, fetches)
+	}
+
+	e.SetUserAgentSelector(nil)
+	got = nil
+	if _, err := e.Extract(server.URL, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"agent-2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the round-robin selection %q, got %q", want, got)
+	}
+
+	e.SetUserAgent("single-agent").SetUserAgentSelector(reverse)
+	got = nil
+	if _, err := e.Extract(server.URL, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"single-agent"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the selector to be ignored without rotation, got %q", got)
+	}
+}
+
+func TestExtractor_SetAcceptHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") == "application/ld+json" {
+			w.Header().Set("Content-Type", "application/ld+json; charset=utf-8")
+			_, _ = w.Write([]byte(`{"@context": "https://schema.org", "@type": "Product", "name": "Anvil"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = fmt.Fprintf(w, `<html><head><meta property="og:title" content="Accept: %s"></head></html>`, r.Header.Get("Accept"))
+	}))
+	defer server.Close()
+
+	e, err := New().Extract(server.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if og := e.OpenGraph(); og == nil || og.Title != "Accept: " {
+		t.Errorf("expected no Accept header by default, got %+v", og)
+	}
+
+	e, err = New().SetAcceptHeader("application/ld+json").Extract(server.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []map[string]any{{"@context": "https://schema.org", "@type": "Product", "name": "Anvil"}}
+	if got := e.JSONLD(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the JSON-LD document %v, got %v", want, got)
+	}
+	if got := e.FoundSyntaxes(); !reflect.DeepEqual(got, []Syntax{SyntaxJSONLD}) {
+		t.Errorf("expected only the JSON-LD syntax, got %v", got)
+	}
+	if errs := e.GetErrors(); len(errs) > 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
 func TestExtractor_SetFetchTimeout(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -137,159 +385,958 @@ func TestExtractor_SetFetchTimeout(t *testing.T) {
 	}
 }
 
-func TestExtractor_Extract(t *testing.T) {
-	server := testServer()
-	defer server.Close()
+func TestExtractor_SetMaxRedirects(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxRedirects int
+		want         int
+	}{
+		{
+			name:         "PositiveMaxRedirects",
+			maxRedirects: 5,
+			want:         5,
+		},
+		{
+			name:         "ZeroMaxRedirects",
+			maxRedirects: 0,
+			want:         0,
+		},
+		{
+			name:         "NegativeMaxRedirects",
+			maxRedirects: -1,
+			want:         0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := New()
+			e.SetMaxRedirects(test.maxRedirects)
+			if e.cfg.maxRedirects != test.want {
+				t.Errorf("expected %v, got %v", test.want, e.cfg.maxRedirects)
+			}
+		})
+	}
+}
 
+func TestExtractor_SetMaxBodyBytes(t *testing.T) {
 	tests := []struct {
-		name      string
-		url       string
-		content   *string
-		err       *string
-		extracted map[Syntax]any
-		errs      []error
+		name     string
+		maxBytes int64
+		want     int64
 	}{
 		{
-			name:      "testServer index page",
-			url:       server.URL,
-			content:   nil,
-			err:       pointerOfString("received HTTP status 404"),
-			extracted: map[Syntax]any{},
-			errs:      []error{errors.New("received HTTP status 404")},
+			name:     "PositiveMaxBodyBytes",
+			maxBytes: 1024,
+			want:     1024,
 		},
 		{
-			name:    "page with no structured data",
-			url:     server.URL,
-			content: pointerOfString("<html>error</p></html>"),
-			err:     nil,
-			extracted: map[Syntax]any{
-				"opengraph": nil,
-				"xcards":    nil,
-				"json-ld":   []map[string]any(nil),
-				"microdata": []extract.MicrodataItem(nil),
-			},
-			errs: nil,
+			name:     "ZeroMaxBodyBytes",
+			maxBytes: 0,
+			want:     0,
 		},
 		{
-			name:    "test-01-opengraph-minimal",
-			url:     fmt.Sprintf("%s/test-01-opengraph-minimal.html", server.URL),
-			content: nil,
-			err:     nil,
-			extracted: map[Syntax]any{
-				"opengraph": &extract.OpenGraph{
-					Type:  "website",
-					Title: "go-microdata-extract",
-					URL:   "https://github.com/aafeher/go-microdata-extract",
-				},
-				"xcards": &extract.XCards{
-					Type:  "website",
-					Title: "go-microdata-extract",
-					URL:   "https://github.com/aafeher/go-microdata-extract",
-				},
-				"json-ld":   []map[string]any(nil),
-				"microdata": []extract.MicrodataItem(nil),
-			},
-			errs: nil,
+			name:     "NegativeMaxBodyBytes",
+			maxBytes: -1,
+			want:     0,
 		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := New()
+			e.SetMaxBodyBytes(test.maxBytes)
+			if e.cfg.maxBodyBytes != test.want {
+				t.Errorf("expected %v, got %v", test.want, e.cfg.maxBodyBytes)
+			}
+		})
+	}
+}
+
+func TestExtractor_SetRetry(t *testing.T) {
+	tests := []struct {
+		name        string
+		attempts    int
+		backoff     time.Duration
+		wantAttempt int
+		wantBackoff time.Duration
+	}{
 		{
-			name:    "test-02-opengraph-optional",
-			url:     fmt.Sprintf("%s/test-02-opengraph-optional.html", server.URL),
-			content: nil,
-			err:     nil,
-			extracted: map[Syntax]any{
-				"opengraph": &extract.OpenGraph{
-					Type:        `website`,
-					Title:       `go-microdata-extract`,
-					URL:         `https://github.com/aafeher/go-microdata-extract`,
-					Description: `OpenGraph with optional metadata`,
-					Determiner:  "the",
-					OpenGraphImage: []extract.OpenGraphImage{
-						{
-							URL: "https://picsum.photos/200/300",
-						},
-						{
-							URL: "https://picsum.photos/210/310",
-						},
-					},
-					Locale: "en_GB",
-					LocaleAlternate: []string{
-						"hu_HU",
-						"fr_FR",
-					},
-					SiteName: "go-microdata-extract",
-					OpenGraphAudio: []extract.OpenGraphAudio{
-						{
-							URL: "https://example.com/bond/theme.mp3",
-						},
-					},
-					OpenGraphVideo: []extract.OpenGraphVideo{
-						{
-							URL: "https://example.com/bond/trailer.swf",
-						},
-					},
-				},
-				"xcards": &extract.XCards{
-					Type:        `website`,
-					Title:       `go-microdata-extract`,
-					URL:         `https://github.com/aafeher/go-microdata-extract`,
-					Description: `OpenGraph with optional metadata`,
-					Determiner:  "the",
-					OpenGraphImage: []extract.OpenGraphImage{
-						{
-							URL: "https://picsum.photos/200/300",
-						},
-						{
-							URL: "https://picsum.photos/210/310",
-						},
-					},
-					Locale: "en_GB",
-					LocaleAlternate: []string{
-						"hu_HU",
-						"fr_FR",
-					},
-					SiteName: "go-microdata-extract",
-					OpenGraphAudio: []extract.OpenGraphAudio{
-						{
-							URL: "https://example.com/bond/theme.mp3",
-						},
-					},
-					OpenGraphVideo: []extract.OpenGraphVideo{
-						{
-							URL: "https://example.com/bond/trailer.swf",
-						},
-					},
-				},
-				"json-ld":   []map[string]any(nil),
-				"microdata": []extract.MicrodataItem(nil),
-			},
-			errs: nil,
+			name:        "PositiveAttempts",
+			attempts:    3,
+			backoff:     time.Second,
+			wantAttempt: 3,
+			wantBackoff: time.Second,
 		},
 		{
-			name:    "test-03-opengraph-image",
-			url:     fmt.Sprintf("%s/test-03-opengraph-image.html", server.URL),
-			content: nil,
-			err:     nil,
-			extracted: map[Syntax]any{
-				"opengraph": &extract.OpenGraph{
-					Type:        `website`,
-					Title:       `go-microdata-extract`,
-					URL:         `https://github.com/aafeher/go-microdata-extract`,
-					Description: `OpenGraph with image`,
-					OpenGraphImage: []extract.OpenGraphImage{
-						{
-							URL: "https://picsum.photos/200/300",
-						},
-						{
-							URL:       "https://picsum.photos/210/310",
-							SecureURL: "https://picsum.photos/210/310",
-							Type:      "image/jpeg",
-							Width:     210,
-							Height:    310,
-							Alt:       "image for testing",
-						},
-					},
-				},
-				"xcards": &extract.XCards{
+			name:        "ZeroAttempts",
+			attempts:    0,
+			backoff:     time.Second,
+			wantAttempt: 1,
+			wantBackoff: time.Second,
+		},
+		{
+			name:        "NegativeBackoff",
+			attempts:    2,
+			backoff:     -time.Second,
+			wantAttempt: 2,
+			wantBackoff: 0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := New()
+			e.SetRetry(test.attempts, test.backoff)
+			if e.cfg.retryAttempts != test.wantAttempt || e.cfg.retryBackoff != test.wantBackoff {
+				t.Errorf("expected %v and %v, got %v and %v", test.wantAttempt, test.wantBackoff, e.cfg.retryAttempts, e.cfg.retryBackoff)
+			}
+		})
+	}
+}
+
+func TestExtractor_Extract_retry(t *testing.T) {
+	content := `<html><head><meta property="og:title" content="Title"></head></html>`
+	// failingServer responds with the given statuses, then with the content, counting the requests
+	failingServer := func(requests *int32, header http.Header, statuses ...int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := int(atomic.AddInt32(requests, 1))
+			if n <= len(statuses) {
+				for key, values := range header {
+					w.Header()[key] = values
+				}
+				w.WriteHeader(statuses[n-1])
+				return
+			}
+			_, _ = w.Write([]byte(content))
+		}))
+	}
+
+	t.Run("fails twice then succeeds", func(t *testing.T) {
+		var requests int32
+		server := failingServer(&requests, nil, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+		defer server.Close()
+
+		e, err := New().SetRetry(3, 10*time.Millisecond).Extract(server.URL, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n := atomic.LoadInt32(&requests); n != 3 {
+			t.Errorf("expected 3 requests, got %d", n)
+		}
+		if e.GetExtracted()[SyntaxOpenGraph] == nil {
+			t.Errorf("expected opengraph result, got %v", e.GetExtracted())
+		}
+	})
+
+	t.Run("attempts exhausted", func(t *testing.T) {
+		var requests int32
+		server := failingServer(&requests, nil, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+		defer server.Close()
+
+		_, err := New().SetRetry(2, time.Millisecond).Extract(server.URL, nil)
+		var statusErr *HTTPStatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadGateway {
+			t.Fatalf("expected *HTTPStatusError with status 502, got %v", err)
+		}
+		if n := atomic.LoadInt32(&requests); n != 2 {
+			t.Errorf("expected 2 requests, got %d", n)
+		}
+	})
+
+	t.Run("client error not retried", func(t *testing.T) {
+		var requests int32
+		server := failingServer(&requests, nil, http.StatusNotFound)
+		defer server.Close()
+
+		_, err := New().SetRetry(3, time.Millisecond).Extract(server.URL, nil)
+		var statusErr *HTTPStatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+			t.Fatalf("expected *HTTPStatusError with status 404, got %v", err)
+		}
+		if n := atomic.LoadInt32(&requests); n != 1 {
+			t.Errorf("expected 1 request, got %d", n)
+		}
+	})
+
+	t.Run("Retry-After honored", func(t *testing.T) {
+		var requests int32
+		server := failingServer(&requests, http.Header{"Retry-After": {"0"}}, http.StatusTooManyRequests)
+		defer server.Close()
+
+		start := time.Now()
+		_, err := New().SetRetry(2, time.Minute).Extract(server.URL, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("expected the Retry-After delay over the backoff, took %s", elapsed)
+		}
+	})
+
+	t.Run("huge Retry-After capped", func(t *testing.T) {
+		var requests int32
+		server := failingServer(&requests, http.Header{"Retry-After": {"86400"}}, http.StatusServiceUnavailable)
+		defer server.Close()
+
+		start := time.Now()
+		_, err := New().SetFetchTimeout(1).SetRetry(2, time.Millisecond).Extract(server.URL, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("expected the Retry-After delay to be capped at the fetch timeout, took %s", elapsed)
+		}
+		if n := atomic.LoadInt32(&requests); n != 2 {
+			t.Errorf("expected 2 requests, got %d", n)
+		}
+	})
+
+	t.Run("cancelled retry", func(t *testing.T) {
+		var requests int32
+		server := failingServer(&requests, nil, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		start := time.Now()
+		_, err := New().SetRetry(3, time.Minute).ExtractContext(ctx, server.URL, nil)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("expected a prompt return, took %s", elapsed)
+		}
+		if n := atomic.LoadInt32(&requests); n != 1 {
+			t.Errorf("expected 1 request, got %d", n)
+		}
+	})
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter string
+		want       time.Duration
+		wantOK     bool
+	}{
+		{name: "empty", retryAfter: "", want: 0, wantOK: false},
+		{name: "seconds", retryAfter: "120", want: 2 * time.Minute, wantOK: true},
+		{name: "negative seconds", retryAfter: "-1", want: 0, wantOK: false},
+		{name: "past date", retryAfter: "Wed, 21 Oct 2015 07:28:00 GMT", want: 0, wantOK: true},
+		{name: "invalid", retryAfter: "soon", want: 0, wantOK: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(test.retryAfter)
+			if got != test.want || ok != test.wantOK {
+				t.Errorf("expected %v and %v, got %v and %v", test.want, test.wantOK, got, ok)
+			}
+		})
+	}
+}
+
+func Test_capRetryAfter(t *testing.T) {
+	tests := []struct {
+		name    string
+		wait    time.Duration
+		timeout time.Duration
+		want    time.Duration
+	}{
+		{name: "below the timeout", wait: time.Second, timeout: 3 * time.Second, want: time.Second},
+		{name: "above the timeout", wait: 24 * time.Hour, timeout: 3 * time.Second, want: 3 * time.Second},
+		{name: "without a timeout", wait: 24 * time.Hour, timeout: 0, want: maxRetryAfter},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := capRetryAfter(test.wait, test.timeout); got != test.want {
+				t.Errorf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestExtractor_SetParseTimeout(t *testing.T) {
+	tests := []struct {
+		name         string
+		parseTimeout time.Duration
+	}{
+		{
+			name:         "no deadline",
+			parseTimeout: 0,
+		},
+		{
+			name:         "deadline",
+			parseTimeout: 100 * time.Millisecond,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := New()
+			e.SetParseTimeout(test.parseTimeout)
+			if e.cfg.parseTimeout != test.parseTimeout {
+				t.Errorf("expected %v, got %v", test.parseTimeout, e.cfg.parseTimeout)
+			}
+		})
+	}
+}
+
+func TestExtractor_Extract_parseTimeout(t *testing.T) {
+	// deeply nested microdata items
+	content := "<html><body>" + strings.Repeat(`<div itemscope><span itemprop="name">x</span>`, 10000) + "</body></html>"
+
+	t.Run("exceeded", func(t *testing.T) {
+		e, err := New().SetParseTimeout(time.Millisecond).Extract("https://example.com/", &content)
+		if !errors.Is(err, ErrParseTimeout) {
+			t.Fatalf("expected an error wrapping ErrParseTimeout, got %v", err)
+		}
+		if _, ok := e.GetExtracted()[SyntaxMicrodata]; ok {
+			t.Errorf("expected no microdata result, got %v", e.GetExtracted()[SyntaxMicrodata])
+		}
+	})
+
+	t.Run("reused after exceeded", func(t *testing.T) {
+		e := New().SetParseTimeout(time.Millisecond)
+		if _, err := e.Extract("https://example.com/", &content); !errors.Is(err, ErrParseTimeout) {
+			t.Fatalf("expected an error wrapping ErrParseTimeout, got %v", err)
+		}
+
+		// the abandoned parsers keep running on their own copy of the content while the Extractor is reused
+		small := `<html><body><div itemscope><span itemprop="name">y</span></div></body></html>`
+		if _, err := e.SetParseTimeout(10*time.Second).Extract("https://example.com/other", &small); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := e.GetExtracted()[SyntaxMicrodata]; !ok {
+			t.Errorf("expected microdata result, got %v", e.GetExtracted())
+		}
+	})
+
+	t.Run("not exceeded", func(t *testing.T) {
+		small := `<html><body><div itemscope><span itemprop="name">x</span></div></body></html>`
+		e, err := New().SetParseTimeout(10*time.Second).Extract("https://example.com/", &small)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := e.GetExtracted()[SyntaxMicrodata]; !ok {
+			t.Errorf("expected microdata result, got %v", e.GetExtracted())
+		}
+	})
+}
+
+func TestExtractor_SetJSONLDPropagateContext(t *testing.T) {
+	tests := []struct {
+		name      string
+		propagate bool
+	}{
+		{
+			name:      "enabled",
+			propagate: true,
+		},
+		{
+			name:      "disabled",
+			propagate: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := New()
+			e.SetJSONLDPropagateContext(test.propagate)
+			if e.cfg.parserOptions.JSONLDPropagateContext != test.propagate {
+				t.Errorf("expected %v, got %v", test.propagate, e.cfg.parserOptions.JSONLDPropagateContext)
+			}
+		})
+	}
+}
+
+func TestExtractor_SetJSONLDPassthrough(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/test-41-ldjson-array-context.html", server.URL)
+	normalized, err := New().SetJSONLDPropagateContext(true).Extract(url, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	passthrough, err := New().SetJSONLDPropagateContext(true).SetJSONLDPassthrough(true).Extract(url, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	normalizedNodes := normalized.GetExtracted()[SyntaxJSONLD].([]map[string]any)
+	passthroughNodes := passthrough.GetExtracted()[SyntaxJSONLD].([]map[string]any)
+	if len(normalizedNodes) != 3 || len(passthroughNodes) != 3 {
+		t.Fatalf("expected 3 nodes each, got %v and %v", normalizedNodes, passthroughNodes)
+	}
+	if got := normalizedNodes[1]["@context"]; got != "https://schema.org" {
+		t.Errorf("expected the normalized node to get the propagated @context, got %v", got)
+	}
+	if got, ok := passthroughNodes[1]["@context"]; ok {
+		t.Errorf("expected the passthrough node to have no @context, got %v", got)
+	}
+	if !reflect.DeepEqual(normalizedNodes[0], passthroughNodes[0]) || !reflect.DeepEqual(normalizedNodes[2], passthroughNodes[2]) {
+		t.Errorf("expected the nodes with their own @context to be equal, got %v and %v", normalizedNodes, passthroughNodes)
+	}
+}
+
+func TestExtractor_SetJSONLDFlattenGraphs(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/test-66-ldjson-graph.html", server.URL)
+	flattened, err := New().Extract(url, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	kept, err := New().SetJSONLDFlattenGraphs(false).Extract(url, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if nodes := flattened.JSONLD(); len(nodes) != 4 {
+		t.Errorf("expected 4 flattened nodes, got %v", nodes)
+	}
+	nodes := kept.JSONLD()
+	if len(nodes) != 2 {
+		t.Fatalf("expected 2 nodes with the @graph kept, got %v", nodes)
+	}
+	if _, ok := nodes[0]["@graph"]; !ok {
+		t.Errorf("expected the @graph container to be kept, got %v", nodes[0])
+	}
+}
+
+func TestExtractor_SetJSONLDExpand(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/test-88-ldjson-expand.html", server.URL)
+	e, err := New().SetJSONLDExpand(true).Extract(url, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	nodes := e.JSONLD()
+	if len(nodes) != 2 {
+		t.Fatalf("expected 2 nodes, got %v", nodes)
+	}
+	if got := nodes[0]["https://schema.org/headline"]; got != "Expanded" {
+		t.Errorf("expected the expanded headline, got %v", nodes[0])
+	}
+	if got, ok := nodes[0]["@context"]; ok {
+		t.Errorf("expected no @context, got %v", got)
+	}
+}
+
+func TestExtractor_SetJSONLDNormalizeType(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/test-95-ldjson-type-array.html", server.URL)
+	e, err := New().SetJSONLDNormalizeType(true).Extract(url, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	nodes := e.JSONLD()
+	if len(nodes) != 2 {
+		t.Fatalf("expected 2 nodes, got %v", nodes)
+	}
+	if got, ok := nodes[0]["@type"].(string); !ok || got != "Product" {
+		t.Errorf("expected the single type as a string, got %v", nodes[0]["@type"])
+	}
+	if got := e.JSONLDByType("IndividualProduct"); len(got) != 1 {
+		t.Errorf("expected the node of several types to be found by type, got %v", got)
+	}
+}
+
+func TestExtractor_SetMaxJSONLDBlockBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxBytes int
+	}{
+		{
+			name:     "limited",
+			maxBytes: 1 << 20,
+		},
+		{
+			name:     "unlimited",
+			maxBytes: 0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := New()
+			e.SetMaxJSONLDBlockBytes(test.maxBytes)
+			if e.cfg.parserOptions.JSONLDMaxBlockBytes != test.maxBytes {
+				t.Errorf("expected %v, got %v", test.maxBytes, e.cfg.parserOptions.JSONLDMaxBlockBytes)
+			}
+		})
+	}
+}
+
+func TestExtractor_SetMaxJSONLDBlocks(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxBlocks int
+	}{
+		{
+			name:      "limited",
+			maxBlocks: 1,
+		},
+		{
+			name:      "unlimited",
+			maxBlocks: 0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := New()
+			e.SetMaxJSONLDBlocks(test.maxBlocks)
+			if e.cfg.parserOptions.JSONLDMaxBlocks != test.maxBlocks {
+				t.Errorf("expected %v, got %v", test.maxBlocks, e.cfg.parserOptions.JSONLDMaxBlocks)
+			}
+		})
+	}
+}
+
+func TestExtractor_SetPreferContentAttribute(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefer bool
+	}{
+		{
+			name:   "enabled",
+			prefer: true,
+		},
+		{
+			name:   "disabled",
+			prefer: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := New()
+			e.SetPreferContentAttribute(test.prefer)
+			if e.cfg.parserOptions.MicrodataPreferContent != test.prefer {
+				t.Errorf("expected %v, got %v", test.prefer, e.cfg.parserOptions.MicrodataPreferContent)
+			}
+		})
+	}
+}
+
+func TestExtractor_SetStrictOpenGraph(t *testing.T) {
+	tests := []struct {
+		name   string
+		strict bool
+	}{
+		{
+			name:   "strict",
+			strict: true,
+		},
+		{
+			name:   "not strict",
+			strict: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := New()
+			e.SetStrictOpenGraph(test.strict)
+			if e.cfg.parserOptions.OpenGraphStrict != test.strict {
+				t.Errorf("expected %v, got %v", test.strict, e.cfg.parserOptions.OpenGraphStrict)
+			}
+		})
+	}
+}
+
+func TestExtractor_SetDedupeMedia(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/test-87-opengraph-duplicate-media.html", server.URL)
+
+	tests := []struct {
+		name       string
+		dedupe     bool
+		wantImages int
+	}{
+		{
+			name:       "dedupe",
+			dedupe:     true,
+			wantImages: 2,
+		},
+		{
+			name:       "no dedupe",
+			dedupe:     false,
+			wantImages: 4,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := New().SetDedupeMedia(test.dedupe).Extract(url, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if e.cfg.parserOptions.OpenGraphDedupeMedia != test.dedupe {
+				t.Errorf("expected %v, got %v", test.dedupe, e.cfg.parserOptions.OpenGraphDedupeMedia)
+			}
+			if images := e.OpenGraph().OpenGraphImage; len(images) != test.wantImages {
+				t.Errorf("expected %d images, got %+v", test.wantImages, images)
+			}
+		})
+	}
+}
+
+func TestExtractor_SetStrict(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/test-91-opengraph-malformed.html", server.URL)
+
+	e, err := New().Extract(url, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if errs := e.GetErrors(); errs != nil {
+		t.Errorf("expected no errors when not strict, got %v", errs)
+	}
+
+	e, err = New().SetStrict(true).Extract(url, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !e.cfg.parserOptions.OpenGraphStrict {
+		t.Errorf("expected the Open Graph validation to be enabled")
+	}
+	var invalid, unknown, missing int
+	for _, err := range e.GetErrors() {
+		switch {
+		case errors.Is(err, extract.ErrInvalidOpenGraphValue):
+			invalid++
+		case errors.Is(err, extract.ErrUnknownOpenGraphProperty):
+			unknown++
+		case errors.Is(err, extract.ErrMissingOpenGraphProperty):
+			missing++
+		}
+	}
+	if invalid != 2 || unknown != 1 || missing != 1 {
+		t.Errorf("expected 2 invalid values, 1 unknown and 1 missing property, got %v", e.GetErrors())
+	}
+}
+
+func TestExtractor_ValidateOpenGraph(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		fixture  string
+		wantErrs int
+	}{
+		{
+			name:     "complete",
+			fixture:  "test-92-opengraph-required.html",
+			wantErrs: 0,
+		},
+		{
+			name:     "missing og:image",
+			fixture:  "test-01-opengraph-minimal.html",
+			wantErrs: 1,
+		},
+		{
+			name:     "no Open Graph",
+			fixture:  "test-33-w3cmicrodata-simple.html",
+			wantErrs: 4,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := New().Extract(fmt.Sprintf("%s/%s", server.URL, test.fixture), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			errs := e.ValidateOpenGraph()
+			if len(errs) != test.wantErrs {
+				t.Fatalf("expected %d errors, got %v", test.wantErrs, errs)
+			}
+			for _, err := range errs {
+				if !errors.Is(err, extract.ErrMissingOpenGraphProperty) {
+					t.Errorf("expected ErrMissingOpenGraphProperty, got %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractor_SetXCardsMergeOpenGraph(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/test-48-social-og-xcards.html", server.URL)
+
+	tests := []struct {
+		name  string
+		merge bool
+		want  *extract.XCards
+	}{
+		{
+			name:  "merge",
+			merge: true,
+			want: &extract.XCards{
+				Card:        "summary_large_image",
+				Site:        "@imdb",
+				Type:        "website",
+				Title:       "The Rock",
+				URL:         "https://www.imdb.com/title/tt0117500/",
+				Description: "The Rock (1996)",
+				OpenGraphImage: []extract.OpenGraphImage{
+					{URL: "https://ia.media-imdb.com/images/rock.jpg"},
+				},
+				XCardsImage: []extract.XCardsImage{
+					{URL: "https://ia.media-imdb.com/images/rock.jpg"},
+				},
+			},
+		},
+		{
+			name:  "do not merge",
+			merge: false,
+			want: &extract.XCards{
+				Card:        "summary_large_image",
+				Site:        "@imdb",
+				Title:       "The Rock",
+				Description: "The Rock (1996)",
+				XCardsImage: []extract.XCardsImage{
+					{URL: "https://ia.media-imdb.com/images/rock.jpg"},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := New().SetXCardsMergeOpenGraph(test.merge).Extract(url, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := e.GetExtracted()[SyntaxXCards]; !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %+v, got %+v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestExtractor_SetFetchOEmbed(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	pageURL := fmt.Sprintf("%s/test-79-oembed.html", server.URL)
+	endpoint := fmt.Sprintf("%s/test-79-oembed.json?content-type=application/json", server.URL)
+
+	t.Run("discovery only", func(t *testing.T) {
+		e, err := New().SetSyntaxes([]Syntax{SyntaxOEmbed}).Extract(pageURL, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := &extract.OEmbed{Endpoint: endpoint}
+		if got := e.GetExtracted()[SyntaxOEmbed]; !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %+v, got %+v", want, got)
+		}
+	})
+
+	t.Run("fetch", func(t *testing.T) {
+		e, err := New().SetSyntaxes([]Syntax{SyntaxOEmbed}).SetFetchOEmbed(true).Extract(pageURL, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := &extract.OEmbed{
+			Endpoint: endpoint,
+			Data: map[string]any{
+				"version":       "1.0",
+				"type":          "video",
+				"title":         "Sample video",
+				"provider_name": "Example",
+				"width":         float64(640),
+				"height":        float64(360),
+				"html":          `<iframe src="https://example.com/embed/video"></iframe>`,
+			},
+		}
+		if got := e.GetExtracted()[SyntaxOEmbed]; !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %+v, got %+v", want, got)
+		}
+		if e.FinalURL() != pageURL {
+			t.Errorf("expected the final URL of the page %q, got %q", pageURL, e.FinalURL())
+		}
+		if contentType := e.ResponseHeader().Get("Content-Type"); !strings.HasPrefix(contentType, "text/html") {
+			t.Errorf("expected the response of the page, got Content-Type %q", contentType)
+		}
+	})
+
+	t.Run("fetch error", func(t *testing.T) {
+		content := `<link rel="alternate" type="application/json+oembed" href="/missing.json">`
+		e, err := New().SetSyntaxes([]Syntax{SyntaxOEmbed}).SetFetchOEmbed(true).Extract(server.URL, &content)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := &extract.OEmbed{Endpoint: server.URL + "/missing.json"}
+		if got := e.GetExtracted()[SyntaxOEmbed]; !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %+v, got %+v", want, got)
+		}
+		var statusErr *HTTPStatusError
+		if errs := e.GetErrors(); len(errs) != 1 || !errors.As(errs[0], &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+			t.Errorf("expected an *HTTPStatusError with status 404, got %v", errs)
+		}
+	})
+}
+
+func TestExtractor_Extract(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		url       string
+		content   *string
+		err       *string
+		extracted map[Syntax]any
+		errs      []error
+	}{
+		{
+			name:      "testServer index page",
+			url:       server.URL,
+			content:   nil,
+			err:       pointerOfString("received HTTP status 404"),
+			extracted: map[Syntax]any{},
+			errs:      []error{&HTTPStatusError{StatusCode: 404, URL: server.URL}},
+		},
+		{
+			name:    "page with no structured data",
+			url:     server.URL,
+			content: pointerOfString("<html>error</p></html>"),
+			err:     nil,
+			extracted: map[Syntax]any{
+				"opengraph": nil,
+				"xcards":    nil,
+				"json-ld":   []map[string]any(nil),
+				"microdata": []extract.MicrodataItem(nil),
+			},
+			errs: nil,
+		},
+		{
+			name:    "test-01-opengraph-minimal",
+			url:     fmt.Sprintf("%s/test-01-opengraph-minimal.html", server.URL),
+			content: nil,
+			err:     nil,
+			extracted: map[Syntax]any{
+				"opengraph": &extract.OpenGraph{
+					Type:  "website",
+					Title: "go-microdata-extract",
+					URL:   "https://github.com/aafeher/go-microdata-extract",
+				},
+				"xcards": &extract.XCards{
+					Type:  "website",
+					Title: "go-microdata-extract",
+					URL:   "https://github.com/aafeher/go-microdata-extract",
+				},
+				"json-ld":   []map[string]any(nil),
+				"microdata": []extract.MicrodataItem(nil),
+			},
+			errs: nil,
+		},
+		{
+			name:    "test-02-opengraph-optional",
+			url:     fmt.Sprintf("%s/test-02-opengraph-optional.html", server.URL),
+			content: nil,
+			err:     nil,
+			extracted: map[Syntax]any{
+				"opengraph": &extract.OpenGraph{
+					Type:        `website`,
+					Title:       `go-microdata-extract`,
+					URL:         `https://github.com/aafeher/go-microdata-extract`,
+					Description: `OpenGraph with optional metadata`,
+					Determiner:  "the",
+					OpenGraphImage: []extract.OpenGraphImage{
+						{
+							URL: "https://picsum.photos/200/300",
+						},
+						{
+							URL: "https://picsum.photos/210/310",
+						},
+					},
+					Locale: "en_GB",
+					LocaleAlternate: []string{
+						"hu_HU",
+						"fr_FR",
+					},
+					SiteName: "go-microdata-extract",
+					OpenGraphAudio: []extract.OpenGraphAudio{
+						{
+							URL: "https://example.com/bond/theme.mp3",
+						},
+					},
+					OpenGraphVideo: []extract.OpenGraphVideo{
+						{
+							URL: "https://example.com/bond/trailer.swf",
+						},
+					},
+				},
+				"xcards": &extract.XCards{
+					Type:        `website`,
+					Title:       `go-microdata-extract`,
+					URL:         `https://github.com/aafeher/go-microdata-extract`,
+					Description: `OpenGraph with optional metadata`,
+					Determiner:  "the",
+					OpenGraphImage: []extract.OpenGraphImage{
+						{
+							URL: "https://picsum.photos/200/300",
+						},
+						{
+							URL: "https://picsum.photos/210/310",
+						},
+					},
+					Locale: "en_GB",
+					LocaleAlternate: []string{
+						"hu_HU",
+						"fr_FR",
+					},
+					SiteName: "go-microdata-extract",
+					OpenGraphAudio: []extract.OpenGraphAudio{
+						{
+							URL: "https://example.com/bond/theme.mp3",
+						},
+					},
+					OpenGraphVideo: []extract.OpenGraphVideo{
+						{
+							URL: "https://example.com/bond/trailer.swf",
+						},
+					},
+				},
+				"json-ld":   []map[string]any(nil),
+				"microdata": []extract.MicrodataItem(nil),
+			},
+			errs: nil,
+		},
+		{
+			name:    "test-03-opengraph-image",
+			url:     fmt.Sprintf("%s/test-03-opengraph-image.html", server.URL),
+			content: nil,
+			err:     nil,
+			extracted: map[Syntax]any{
+				"opengraph": &extract.OpenGraph{
+					Type:        `website`,
+					Title:       `go-microdata-extract`,
+					URL:         `https://github.com/aafeher/go-microdata-extract`,
+					Description: `OpenGraph with image`,
+					OpenGraphImage: []extract.OpenGraphImage{
+						{
+							URL: "https://picsum.photos/200/300",
+						},
+						{
+							URL:       "https://picsum.photos/210/310",
+							SecureURL: "https://picsum.photos/210/310",
+							Type:      "image/jpeg",
+							Width:     210,
+							Height:    310,
+							Alt:       "image for testing",
+						},
+					},
+				},
+				"xcards": &extract.XCards{
 					Type:        `website`,
 					Title:       `go-microdata-extract`,
 					URL:         `https://github.com/aafeher/go-microdata-extract`,
@@ -429,6 +1476,9 @@ func TestExtractor_Extract(t *testing.T) {
 							"http://open.spotify.com/artist/0oSGxfWSnnOXhD2fKuz2Gy",
 						},
 					},
+					Extra: map[string][]string{
+						"fb:app_id": {"174829003346"},
+					},
 				},
 				"xcards": &extract.XCards{
 					Type:     `music.song`,
@@ -489,7 +1539,10 @@ func TestExtractor_Extract(t *testing.T) {
 								Track: 2,
 							},
 						},
-						ReleaseDate: "2011-04-19",
+						ReleaseDate: time.Date(2011, 4, 19, 0, 0, 0, 0, time.UTC),
+					},
+					Extra: map[string][]string{
+						"fb:app_id": {"174829003346"},
 					},
 				},
 				"xcards": &extract.XCards{
@@ -519,7 +1572,7 @@ func TestExtractor_Extract(t *testing.T) {
 								Track: 2,
 							},
 						},
-						ReleaseDate: "2011-04-19",
+						ReleaseDate: time.Date(2011, 4, 19, 0, 0, 0, 0, time.UTC),
 					},
 				},
 				"json-ld":   []map[string]any(nil),
@@ -548,6 +1601,9 @@ func TestExtractor_Extract(t *testing.T) {
 							"http://open.spotify.com/user/austinhaugen",
 						},
 					},
+					Extra: map[string][]string{
+						"fb:app_id": {"174829003346"},
+					},
 				},
 				"xcards": &extract.XCards{
 					Type:     `music.playlist`,
@@ -1108,7 +2164,7 @@ func TestExtractor_Extract(t *testing.T) {
 								Track: 2,
 							},
 						},
-						ReleaseDate: "2011-04-19",
+						ReleaseDate: time.Date(2011, 4, 19, 0, 0, 0, 0, time.UTC),
 					},
 				},
 				"json-ld":   []map[string]any(nil),
@@ -1504,186 +2560,525 @@ func TestExtractor_Extract(t *testing.T) {
 			},
 		},
 		{
-			name:    "test-33-w3cmicrodata-simple",
-			url:     fmt.Sprintf("%s/test-33-w3cmicrodata-simple.html", server.URL),
+			name:    "test-33-w3cmicrodata-simple",
+			url:     fmt.Sprintf("%s/test-33-w3cmicrodata-simple.html", server.URL),
+			content: nil,
+			err:     nil,
+			extracted: map[Syntax]any{
+				"opengraph": nil,
+				"xcards":    nil,
+				"json-ld":   []map[string]any(nil),
+				"microdata": []extract.MicrodataItem{
+					{
+						Type:  "https://schema.org/SoftwareApplication",
+						Types: []string{"https://schema.org/SoftwareApplication"},
+						Properties: map[string]any{
+							"name":                "Angry Birds",
+							"operatingSystem":     "ANDROID",
+							"applicationCategory": "https://schema.org/SoftwareApplication",
+							"aggregateRating": &extract.MicrodataItem{
+								Type:  "https://schema.org/AggregateRating",
+								Types: []string{"https://schema.org/AggregateRating"},
+								ID:    nil,
+								Properties: map[string]any{
+									"ratingValue": "4.6",
+									"ratingCount": "8864",
+								},
+								PropertyOrder: []string{"ratingValue", "ratingCount"},
+							},
+							"offers": &extract.MicrodataItem{
+								Type:  "https://schema.org/Offer",
+								Types: []string{"https://schema.org/Offer"},
+								ID:    nil,
+								Properties: map[string]any{
+									"price":         "1.00",
+									"priceCurrency": "USD",
+								},
+								PropertyOrder: []string{"price", "priceCurrency"},
+							},
+						},
+						PropertyOrder: []string{"name", "operatingSystem", "applicationCategory", "aggregateRating", "offers"},
+					},
+				},
+			},
+			errs: nil,
+		},
+		{
+			name:    "test-34-w3cmicrodata-extended",
+			url:     fmt.Sprintf("%s/test-34-w3cmicrodata-extended.html", server.URL),
+			content: nil,
+			err:     nil,
+			extracted: map[Syntax]any{
+				"opengraph": nil,
+				"xcards":    nil,
+				"json-ld":   []map[string]any(nil),
+				"microdata": []extract.MicrodataItem{
+					{
+						Type:  "https://schema.org/SoftwareApplication",
+						Types: []string{"https://schema.org/SoftwareApplication"},
+						Properties: map[string]any{
+							"name":                "Angry Birds",
+							"operatingSystem":     "ANDROID",
+							"downloadUrl":         fmt.Sprintf("%s/download", server.URL),
+							"applicationCategory": "https://schema.org/SoftwareApplication",
+							"aggregateRating": &extract.MicrodataItem{
+								Type:  "https://schema.org/AggregateRating",
+								Types: []string{"https://schema.org/AggregateRating"},
+								ID:    nil,
+								Properties: map[string]any{
+									"ratingValue": "4.6",
+									"ratingCount": "8864",
+								},
+								PropertyOrder: []string{"ratingValue", "ratingCount"},
+							},
+							"offers": &extract.MicrodataItem{
+								Type:  "https://schema.org/Offer",
+								Types: []string{"https://schema.org/Offer"},
+								ID:    nil,
+								Properties: map[string]any{
+									"price":         "1.00",
+									"priceCurrency": "USD",
+								},
+								PropertyOrder: []string{"price", "priceCurrency"},
+							},
+						},
+						PropertyOrder: []string{"name", "operatingSystem", "downloadUrl", "applicationCategory", "aggregateRating", "offers"},
+					},
+				},
+			},
+			errs: nil,
+		},
+		{
+			name:    "test-35-w3cmicrodata-book",
+			url:     fmt.Sprintf("%s/test-35-w3cmicrodata-book.html", server.URL),
+			content: nil,
+			err:     nil,
+			extracted: map[Syntax]any{
+				"opengraph": nil,
+				"xcards":    nil,
+				"json-ld":   []map[string]any(nil),
+				"microdata": []extract.MicrodataItem{
+					{
+						ID: pointerOfString("urn:isbn:0-374-22848-5\u003c"),
+						Properties: map[string]any{
+							"author":        "Jonathan C Slaght",
+							"datePublished": "2020-08-04",
+							"title":         "Owls of the Eastern Ice",
+							"discussionUrl": "//www.example.com/book/discussion",
+						},
+						PropertyOrder: []string{"title", "author", "datePublished", "discussionUrl"},
+						Type:          "https://schema.org/Book",
+						Types:         []string{"https://schema.org/Book"},
+					},
+				},
+			},
+			errs: nil,
+		},
+		{
+			name:    "test-36-w3cmicrodata-organization",
+			url:     fmt.Sprintf("%s/test-36-w3cmicrodata-organization.html", server.URL),
+			content: nil,
+			err:     nil,
+			extracted: map[Syntax]any{
+				"opengraph": nil,
+				"xcards":    nil,
+				"json-ld":   []map[string]any(nil),
+				"microdata": []extract.MicrodataItem{
+					{
+						ID: pointerOfString("http://example.com/org/1"),
+						Properties: map[string]any{
+							"employee": &extract.MicrodataItem{
+								Type:  "http://schema.org/Person",
+								Types: []string{"http://schema.org/Person"},
+								ID:    pointerOfString("http://example.com/person/1"),
+								Properties: map[string]any{
+									"name": "John Doe",
+								},
+								PropertyOrder: []string{"name"},
+							},
+							"name": "Example Organization",
+						},
+						PropertyOrder: []string{"name", "employee"},
+						Type:          "http://schema.org/Organization",
+						Types:         []string{"http://schema.org/Organization"},
+					},
+				},
+			},
+			errs: nil,
+		},
+		{
+			name:    "test-37-w3cmicrodata-product",
+			url:     fmt.Sprintf("%s/test-37-w3cmicrodata-product.html", server.URL),
+			content: nil,
+			err:     nil,
+			extracted: map[Syntax]any{
+				"opengraph": nil,
+				"xcards":    nil,
+				"json-ld":   []map[string]any(nil),
+				"microdata": []extract.MicrodataItem{
+					{
+						Type:  "http://schema.org/Product",
+						Types: []string{"http://schema.org/Product"},
+						Properties: map[string]any{
+							"aggregateRating": &extract.MicrodataItem{
+								Type:  "http://schema.org/AggregateRating",
+								Types: []string{"http://schema.org/AggregateRating"},
+								Properties: map[string]any{
+									"ratingValue": "3.5",
+									"reviewCount": "11",
+								},
+								PropertyOrder: []string{"ratingValue", "reviewCount"},
+							},
+							"name":       "Panasonic White 60L Refrigerator",
+							"product-id": "9678AOU879",
+						},
+						PropertyOrder: []string{"name", "product-id", "aggregateRating"},
+					},
+				},
+			},
+			errs: nil,
+		},
+		{
+			name:    "test-38-w3cmicrodata-multiple-itemprop",
+			url:     fmt.Sprintf("%s/test-38-w3cmicrodata-multiple-itemprop.html", server.URL),
+			content: nil,
+			err:     nil,
+			extracted: map[Syntax]any{
+				"opengraph": nil,
+				"xcards":    nil,
+				"json-ld":   []map[string]any(nil),
+				"microdata": []extract.MicrodataItem{
+					{
+						Properties: map[string]any{
+							"flavor": []any{
+								"Lemon sorbet",
+								"Apricot sorbet",
+							},
+							"color": []any{
+								"yellow",
+								"green",
+								"purple",
+							},
+						},
+						PropertyOrder: []string{"flavor", "color"},
+					},
+				},
+			},
+			errs: nil,
+		},
+		{
+			name:    "test-52-opengraph-article-author-profile",
+			url:     fmt.Sprintf("%s/test-52-opengraph-article-author-profile.html", server.URL),
+			content: nil,
+			err:     nil,
+			extracted: map[Syntax]any{
+				"opengraph": &extract.OpenGraph{
+					Type:  `article`,
+					Title: `OpenGraph Article Title`,
+					URL:   `https://www.example.com/article/article-title`,
+					Article: &extract.Article{
+						Author: []string{
+							"https://www.example.com/profileAuthorA.html",
+							"https://www.example.com/profileAuthorB.html",
+						},
+						Section: "Front page",
+						AuthorProfile: []extract.ArticleAuthor{
+							{
+								URL: "https://www.example.com/profileAuthorA.html",
+								Profile: extract.Profile{
+									FirstName: "John",
+									LastName:  "Doe",
+									Username:  "johndoe",
+								},
+							},
+						},
+					},
+				},
+				"xcards": &extract.XCards{
+					Type:  `article`,
+					Title: `OpenGraph Article Title`,
+					URL:   `https://www.example.com/article/article-title`,
+					Article: &extract.Article{
+						Author: []string{
+							"https://www.example.com/profileAuthorA.html",
+							"https://www.example.com/profileAuthorB.html",
+						},
+						Section: "Front page",
+						AuthorProfile: []extract.ArticleAuthor{
+							{
+								URL: "https://www.example.com/profileAuthorA.html",
+								Profile: extract.Profile{
+									FirstName: "John",
+									LastName:  "Doe",
+									Username:  "johndoe",
+								},
+							},
+						},
+					},
+				},
+				"json-ld":   []map[string]any(nil),
+				"microdata": []extract.MicrodataItem(nil),
+			},
+			errs: nil,
+		},
+		{
+			name:    "test-55-opengraph-image-secure-url-first",
+			url:     fmt.Sprintf("%s/test-55-opengraph-image-secure-url-first.html", server.URL),
+			content: nil,
+			err:     nil,
+			extracted: map[Syntax]any{
+				"opengraph": &extract.OpenGraph{
+					Type:  `website`,
+					Title: `go-microdata-extract`,
+					URL:   `https://github.com/aafeher/go-microdata-extract`,
+					OpenGraphImage: []extract.OpenGraphImage{
+						{
+							URL:       "http://picsum.photos/200/300",
+							SecureURL: "https://picsum.photos/200/300",
+						},
+						{
+							URL:       "http://picsum.photos/210/310",
+							SecureURL: "https://picsum.photos/210/310",
+							Width:     210,
+							Height:    310,
+						},
+					},
+				},
+				"xcards": &extract.XCards{
+					Type:  `website`,
+					Title: `go-microdata-extract`,
+					URL:   `https://github.com/aafeher/go-microdata-extract`,
+					OpenGraphImage: []extract.OpenGraphImage{
+						{
+							URL:       "http://picsum.photos/200/300",
+							SecureURL: "https://picsum.photos/200/300",
+						},
+						{
+							URL:       "http://picsum.photos/210/310",
+							SecureURL: "https://picsum.photos/210/310",
+							Width:     210,
+							Height:    310,
+						},
+					},
+				},
+				"json-ld":   []map[string]any(nil),
+				"microdata": []extract.MicrodataItem(nil),
+			},
+			errs: nil,
+		},
+		{
+			name:    "test-71-opengraph-product",
+			url:     fmt.Sprintf("%s/test-71-opengraph-product.html", server.URL),
 			content: nil,
 			err:     nil,
 			extracted: map[Syntax]any{
-				"opengraph": nil,
-				"xcards":    nil,
-				"json-ld":   []map[string]any(nil),
-				"microdata": []extract.MicrodataItem{
-					{
-						Type: "https://schema.org/SoftwareApplication",
-						Properties: map[string]any{
-							"name":                "Angry Birds",
-							"operatingSystem":     "ANDROID",
-							"applicationCategory": "",
-							"aggregateRating": &extract.MicrodataItem{
-								Type: "https://schema.org/AggregateRating",
-								ID:   nil,
-								Properties: map[string]any{
-									"ratingValue": "4.6",
-									"ratingCount": "8864",
-								},
-							},
-							"offers": &extract.MicrodataItem{
-								Type: "https://schema.org/Offer",
-								ID:   nil,
-								Properties: map[string]any{
-									"price":         "1.00",
-									"priceCurrency": "USD",
-								},
-							},
+				"opengraph": &extract.OpenGraph{
+					Type:  `product`,
+					Title: `Anvil`,
+					URL:   `https://example.com/products/anvil`,
+					OpenGraphImage: []extract.OpenGraphImage{
+						{
+							URL: "https://example.com/img/anvil.jpg",
 						},
 					},
+					Product: &extract.OpenGraphProduct{
+						PriceAmount:    "119.990",
+						PriceCurrency:  "USD",
+						Availability:   "in stock",
+						RetailerItemID: "ANV-001",
+						Condition:      "new",
+					},
+				},
+				"xcards": &extract.XCards{
+					Type:  `product`,
+					Title: `Anvil`,
+					URL:   `https://example.com/products/anvil`,
+					OpenGraphImage: []extract.OpenGraphImage{
+						{
+							URL: "https://example.com/img/anvil.jpg",
+						},
+					},
+					Product: &extract.OpenGraphProduct{
+						PriceAmount:    "119.990",
+						PriceCurrency:  "USD",
+						Availability:   "in stock",
+						RetailerItemID: "ANV-001",
+						Condition:      "new",
+					},
 				},
+				"json-ld":   []map[string]any(nil),
+				"microdata": []extract.MicrodataItem(nil),
 			},
 			errs: nil,
 		},
 		{
-			name:    "test-34-w3cmicrodata-extended",
-			url:     fmt.Sprintf("%s/test-34-w3cmicrodata-extended.html", server.URL),
+			name:    "test-72-opengraph-image-url",
+			url:     fmt.Sprintf("%s/test-72-opengraph-image-url.html", server.URL),
 			content: nil,
 			err:     nil,
 			extracted: map[Syntax]any{
-				"opengraph": nil,
-				"xcards":    nil,
-				"json-ld":   []map[string]any(nil),
-				"microdata": []extract.MicrodataItem{
-					{
-						Type: "https://schema.org/SoftwareApplication",
-						Properties: map[string]any{
-							"name":                "Angry Birds",
-							"operatingSystem":     "ANDROID",
-							"downloadUrl":         fmt.Sprintf("%s/download", server.URL),
-							"applicationCategory": "",
-							"aggregateRating": &extract.MicrodataItem{
-								Type: "https://schema.org/AggregateRating",
-								ID:   nil,
-								Properties: map[string]any{
-									"ratingValue": "4.6",
-									"ratingCount": "8864",
-								},
-							},
-							"offers": &extract.MicrodataItem{
-								Type: "https://schema.org/Offer",
-								ID:   nil,
-								Properties: map[string]any{
-									"price":         "1.00",
-									"priceCurrency": "USD",
-								},
-							},
+				"opengraph": &extract.OpenGraph{
+					Type:  `website`,
+					Title: `Image URL alias`,
+					URL:   `https://example.com/gallery`,
+					OpenGraphImage: []extract.OpenGraphImage{
+						{
+							URL:    "https://example.com/img/first.jpg",
+							Width:  800,
+							Height: 600,
+						},
+						{
+							URL: "https://example.com/img/second.jpg",
+						},
+					},
+					OpenGraphAudio: []extract.OpenGraphAudio{
+						{
+							URL: "https://example.com/media/track.mp3",
+						},
+					},
+					OpenGraphVideo: []extract.OpenGraphVideo{
+						{
+							URL:  "https://example.com/media/clip.mp4",
+							Type: "video/mp4",
+						},
+					},
+				},
+				"xcards": &extract.XCards{
+					Type:  `website`,
+					Title: `Image URL alias`,
+					URL:   `https://example.com/gallery`,
+					OpenGraphImage: []extract.OpenGraphImage{
+						{
+							URL:    "https://example.com/img/first.jpg",
+							Width:  800,
+							Height: 600,
+						},
+						{
+							URL: "https://example.com/img/second.jpg",
+						},
+					},
+					OpenGraphAudio: []extract.OpenGraphAudio{
+						{
+							URL: "https://example.com/media/track.mp3",
+						},
+					},
+					OpenGraphVideo: []extract.OpenGraphVideo{
+						{
+							URL:  "https://example.com/media/clip.mp4",
+							Type: "video/mp4",
 						},
 					},
 				},
+				"json-ld":   []map[string]any(nil),
+				"microdata": []extract.MicrodataItem(nil),
 			},
 			errs: nil,
 		},
 		{
-			name:    "test-35-w3cmicrodata-book",
-			url:     fmt.Sprintf("%s/test-35-w3cmicrodata-book.html", server.URL),
+			name:    "test-74-opengraph-freshness",
+			url:     fmt.Sprintf("%s/test-74-opengraph-freshness.html", server.URL),
 			content: nil,
 			err:     nil,
 			extracted: map[Syntax]any{
-				"opengraph": nil,
-				"xcards":    nil,
-				"json-ld":   []map[string]any(nil),
-				"microdata": []extract.MicrodataItem{
-					{
-						ID: pointerOfString("urn:isbn:0-374-22848-5\u003c"),
-						Properties: map[string]any{
-							"author":        "Jonathan C Slaght",
-							"datePublished": "2020-08-04",
-							"title":         "Owls of the Eastern Ice",
-							"discussionUrl": "//www.example.com/book/discussion",
-						},
-						Type: "https://schema.org/Book",
-					},
+				"opengraph": &extract.OpenGraph{
+					Type:        `article`,
+					Title:       `Breaking news`,
+					URL:         `https://example.com/news/breaking`,
+					UpdatedTime: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+					TTL:         345600,
 				},
+				"xcards": &extract.XCards{
+					Type:        `article`,
+					Title:       `Breaking news`,
+					URL:         `https://example.com/news/breaking`,
+					UpdatedTime: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+					TTL:         345600,
+				},
+				"json-ld":   []map[string]any(nil),
+				"microdata": []extract.MicrodataItem(nil),
 			},
 			errs: nil,
 		},
 		{
-			name:    "test-36-w3cmicrodata-organization",
-			url:     fmt.Sprintf("%s/test-36-w3cmicrodata-organization.html", server.URL),
+			name:    "test-76-xcards-player",
+			url:     fmt.Sprintf("%s/test-76-xcards-player.html", server.URL),
 			content: nil,
 			err:     nil,
 			extracted: map[Syntax]any{
 				"opengraph": nil,
-				"xcards":    nil,
-				"json-ld":   []map[string]any(nil),
-				"microdata": []extract.MicrodataItem{
-					{
-						ID: pointerOfString("http://example.com/org/1"),
-						Properties: map[string]any{
-							"employee": &extract.MicrodataItem{
-								Type: "http://schema.org/Person",
-								ID:   pointerOfString("http://example.com/person/1"),
-								Properties: map[string]any{
-									"name": "John Doe",
-								},
-							},
-							"name": "Example Organization",
+				"xcards": &extract.XCards{
+					Card:  `player`,
+					Site:  `@example`,
+					Title: `Sample video`,
+					XCardsImage: []extract.XCardsImage{
+						{
+							URL: "https://example.com/img/poster.jpg",
 						},
-						Type: "http://schema.org/Organization",
+					},
+					Player: &extract.XCardsPlayer{
+						URL:    "https://example.com/embed/video",
+						Width:  1280,
+						Height: 720,
+						Stream: "https://example.com/media/video.mp4",
 					},
 				},
+				"json-ld":   []map[string]any(nil),
+				"microdata": []extract.MicrodataItem(nil),
 			},
 			errs: nil,
 		},
 		{
-			name:    "test-37-w3cmicrodata-product",
-			url:     fmt.Sprintf("%s/test-37-w3cmicrodata-product.html", server.URL),
+			name:    "test-77-xcards-app",
+			url:     fmt.Sprintf("%s/test-77-xcards-app.html", server.URL),
 			content: nil,
 			err:     nil,
 			extracted: map[Syntax]any{
 				"opengraph": nil,
-				"xcards":    nil,
-				"json-ld":   []map[string]any(nil),
-				"microdata": []extract.MicrodataItem{
-					{
-						Type: "http://schema.org/Product",
-						Properties: map[string]any{
-							"aggregateRating": &extract.MicrodataItem{
-								Type: "http://schema.org/AggregateRating",
-								Properties: map[string]any{
-									"ratingValue": "3.5",
-									"reviewCount": "11",
-								},
-							},
-							"name":       "Panasonic White 60L Refrigerator",
-							"product-id": "9678AOU879",
+				"xcards": &extract.XCards{
+					Card:        `app`,
+					Site:        `@example`,
+					Description: `The example app`,
+					App: &extract.XCardsApp{
+						IPhone: &extract.XCardsAppPlatform{
+							Name: "Example for iPhone",
+							ID:   "307234931",
+							URL:  "example://action/5149e249222f9e600a7540ef",
+						},
+						IPad: &extract.XCardsAppPlatform{
+							Name: "Example for iPad",
+							ID:   "307234932",
+							URL:  "example://action/5149e249222f9e600a7540ef",
+						},
+						GooglePlay: &extract.XCardsAppPlatform{
+							Name: "Example for Android",
+							ID:   "com.example.android",
+							URL:  "https://example.com/app/5149e249222f9e600a7540ef",
 						},
 					},
 				},
+				"json-ld":   []map[string]any(nil),
+				"microdata": []extract.MicrodataItem(nil),
 			},
 			errs: nil,
 		},
 		{
-			name:    "test-38-w3cmicrodata-multiple-itemprop",
-			url:     fmt.Sprintf("%s/test-38-w3cmicrodata-multiple-itemprop.html", server.URL),
+			name:    "test-78-xcards-labels",
+			url:     fmt.Sprintf("%s/test-78-xcards-labels.html", server.URL),
 			content: nil,
 			err:     nil,
 			extracted: map[Syntax]any{
 				"opengraph": nil,
-				"xcards":    nil,
-				"json-ld":   []map[string]any(nil),
-				"microdata": []extract.MicrodataItem{
-					{
-						Properties: map[string]any{
-							"flavor": []any{
-								"Lemon sorbet",
-								"Apricot sorbet",
-							},
-							"color": []any{
-								"yellow",
-								"green",
-								"purple",
-							},
-						},
+				"xcards": &extract.XCards{
+					Card:  `summary`,
+					Title: `Anvil`,
+					Labels: []extract.XCardsLabel{
+						{Label: "Price", Data: "$9.99"},
+						{Label: "Availability", Data: "In stock"},
+						{Data: "Free shipping"},
 					},
 				},
+				"json-ld":   []map[string]any(nil),
+				"microdata": []extract.MicrodataItem(nil),
 			},
 			errs: nil,
 		},
@@ -1718,8 +3113,236 @@ func TestExtractor_Extract(t *testing.T) {
 			if !reflect.DeepEqual(e.errs, test.errs) {
 				t.Error("errs is not equal to expected value")
 			}
-		})
-	}
+		})
+	}
+}
+
+func TestExtractor_ExtractHTML(t *testing.T) {
+	const snippet = `<div itemscope itemtype="https://schema.org/Product">
+	<span itemprop="name">Anvil</span>
+	<a itemprop="url" href="/products/anvil">Anvil</a>
+</div>`
+
+	tests := []struct {
+		name    string
+		baseURL string
+		wantURL string
+	}{
+		{
+			name:    "without base URL",
+			baseURL: "",
+			wantURL: "/products/anvil",
+		},
+		{
+			name:    "with base URL",
+			baseURL: "https://example.com/shop/",
+			wantURL: "https://example.com/products/anvil",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := New().SetBaseURL(test.baseURL).ExtractHTML(snippet)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			items, ok := e.GetExtracted()[SyntaxMicrodata].([]extract.MicrodataItem)
+			if !ok || len(items) != 1 {
+				t.Fatalf("expected one microdata item, got %v", e.GetExtracted()[SyntaxMicrodata])
+			}
+			if got := items[0].Properties["url"]; got != test.wantURL {
+				t.Errorf("expected %q, got %q", test.wantURL, got)
+			}
+		})
+	}
+}
+
+func TestExtractor_ExtractFromReader(t *testing.T) {
+	content, err := os.ReadFile("./test/test-63-w3cmicrodata-relative-url.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		baseURL string
+		wantURL string
+	}{
+		{
+			name:    "without base URL",
+			baseURL: "",
+			wantURL: "products/anvil.html",
+		},
+		{
+			name:    "with base URL",
+			baseURL: "https://example.com/catalog/",
+			wantURL: "https://example.com/catalog/products/anvil.html",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := New().SetSyntaxes([]Syntax{SyntaxMicrodata}).ExtractFromReader(test.baseURL, bytes.NewReader(content))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			items, ok := e.GetExtracted()[SyntaxMicrodata].([]extract.MicrodataItem)
+			if !ok || len(items) != 1 {
+				t.Fatalf("expected one microdata item, got %v", e.GetExtracted()[SyntaxMicrodata])
+			}
+			if got := items[0].Properties["url"]; got != test.wantURL {
+				t.Errorf("expected %q, got %q", test.wantURL, got)
+			}
+		})
+	}
+
+	t.Run("read error", func(t *testing.T) {
+		readErr := errors.New("stream closed")
+		_, err := New().ExtractFromReader("", iotest.ErrReader(readErr))
+		if !errors.Is(err, readErr) {
+			t.Errorf("expected the read error, got %v", err)
+		}
+	})
+}
+
+func TestExtractor_ExtractFromBytes(t *testing.T) {
+	content, err := os.ReadFile("./test/test-63-w3cmicrodata-relative-url.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	e, err := New().SetSyntaxes([]Syntax{SyntaxMicrodata}).ExtractFromBytes("https://example.com/catalog/", content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	items := e.Microdata()
+	if len(items) != 1 {
+		t.Fatalf("expected one microdata item, got %v", items)
+	}
+	if got, want := items[0].Properties["url"], "https://example.com/catalog/products/anvil.html"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestExtractor_ExtractFromFile(t *testing.T) {
+	content, err := os.ReadFile("./test/test-63-w3cmicrodata-relative-url.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+	var compressed bytes.Buffer
+	gzipWriter := gzip.NewWriter(&compressed)
+	_, _ = gzipWriter.Write(content)
+	_ = gzipWriter.Close()
+	gzipPath := filepath.Join(t.TempDir(), "page.html.gz")
+	if err := os.WriteFile(gzipPath, compressed.Bytes(), 0o600); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		baseURL string
+		wantURL string
+	}{
+		{
+			name:    "plain",
+			path:    "./test/test-63-w3cmicrodata-relative-url.html",
+			wantURL: "products/anvil.html",
+		},
+		{
+			name:    "gzipped",
+			path:    gzipPath,
+			wantURL: "products/anvil.html",
+		},
+		{
+			name:    "with base URL",
+			path:    gzipPath,
+			baseURL: "https://example.com/catalog/",
+			wantURL: "https://example.com/catalog/products/anvil.html",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := New().SetSyntaxes([]Syntax{SyntaxMicrodata}).SetBaseURL(test.baseURL).ExtractFromFile(test.path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			items := e.Microdata()
+			if len(items) != 1 {
+				t.Fatalf("expected one microdata item, got %v", items)
+			}
+			if got := items[0].Properties["url"]; got != test.wantURL {
+				t.Errorf("expected %q, got %q", test.wantURL, got)
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := New().ExtractFromFile(filepath.Join(t.TempDir(), "missing.html"))
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected os.ErrNotExist, got %v", err)
+		}
+	})
+
+	t.Run("invalid gzip", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "page.html.gz")
+		if err := os.WriteFile(path, content, 0o600); err != nil {
+			t.Fatalf("writing fixture: %v", err)
+		}
+		if _, err := New().ExtractFromFile(path); !errors.Is(err, gzip.ErrHeader) {
+			t.Errorf("expected gzip.ErrHeader, got %v", err)
+		}
+	})
+}
+
+func TestExtractor_ExtractContext(t *testing.T) {
+	t.Run("cancelled fetch", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+		}))
+		defer server.Close()
+		defer close(release)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		start := time.Now()
+		_, err := New().SetFetchTimeout(10).ExtractContext(ctx, server.URL, nil)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("expected a prompt return, took %s", elapsed)
+		}
+	})
+
+	t.Run("cancelled parse", func(t *testing.T) {
+		content := "<html><body>" + strings.Repeat(`<div itemscope><span itemprop="name">x</span>`, 10000) + "</body></html>"
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		e, err := New().ExtractContext(ctx, "https://example.com/", &content)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		if _, ok := e.GetExtracted()[SyntaxMicrodata]; ok {
+			t.Errorf("expected no microdata result, got %v", e.GetExtracted()[SyntaxMicrodata])
+		}
+	})
+
+	t.Run("not cancelled", func(t *testing.T) {
+		content := `<html><head><meta property="og:title" content="Title"></head></html>`
+		e, err := New().ExtractContext(context.Background(), "https://example.com/", &content)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if e.GetExtracted()[SyntaxOpenGraph] == nil {
+			t.Errorf("expected opengraph result, got %v", e.GetExtracted())
+		}
+	})
 }
 
 func TestExtractor_setContent(t *testing.T) {
@@ -1770,7 +3393,7 @@ func TestExtractor_setContent(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			s := test.setup()
-			retURLContent, err := s.setContent(test.attrURLContent)
+			retURLContent, err := s.setContent(context.Background(), test.attrURLContent)
 			if retURLContent != test.wantURLContent {
 				t.Errorf("unexpected urlContent: got %v, want %v", retURLContent, test.wantURLContent)
 			}
@@ -1825,6 +3448,12 @@ func TestExtractor_fetch(t *testing.T) {
 			url:     fmt.Sprintf("%s/test-01-opengraph-minimal.html", server.URL),
 			wantErr: false,
 		},
+		{
+			name:    "URL with fragment",
+			fields:  fields{e.cfg},
+			url:     fmt.Sprintf("%s/test-01-opengraph-minimal.html#section", server.URL),
+			wantErr: false,
+		},
 		{
 			name:    "Timeout URL",
 			fields:  fields{config{fetchTimeout: 0}},
@@ -1837,7 +3466,7 @@ func TestExtractor_fetch(t *testing.T) {
 			e := &Extractor{
 				cfg: test.fields.cfg,
 			}
-			_, err := e.fetch(test.url)
+			_, err := e.fetch(context.Background(), test.url)
 			if (err != nil) != test.wantErr {
 				t.Errorf("fetch() error = %v, wantErr %v", err, test.wantErr)
 				return
@@ -1846,6 +3475,404 @@ func TestExtractor_fetch(t *testing.T) {
 	}
 }
 
+func TestExtractor_Extract_redirects(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	t.Run("final URL", func(t *testing.T) {
+		e, err := New().SetSyntaxes([]Syntax{SyntaxMicrodata}).Extract(fmt.Sprintf("%s/redirect/test-63-w3cmicrodata-relative-url.html", server.URL), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := fmt.Sprintf("%s/catalog/test-63-w3cmicrodata-relative-url.html", server.URL); e.FinalURL() != want {
+			t.Errorf("expected final URL %q, got %q", want, e.FinalURL())
+		}
+		items, ok := e.GetExtracted()[SyntaxMicrodata].([]extract.MicrodataItem)
+		if !ok || len(items) != 1 {
+			t.Fatalf("expected one microdata item, got %v", e.GetExtracted()[SyntaxMicrodata])
+		}
+		if want := fmt.Sprintf("%s/catalog/products/anvil.html", server.URL); items[0].Properties["url"] != want {
+			t.Errorf("expected url %q resolved against the final URL, got %q", want, items[0].Properties["url"])
+		}
+	})
+
+	t.Run("not redirected", func(t *testing.T) {
+		url := fmt.Sprintf("%s/test-63-w3cmicrodata-relative-url.html", server.URL)
+		e, err := New().Extract(url, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if e.FinalURL() != url {
+			t.Errorf("expected final URL %q, got %q", url, e.FinalURL())
+		}
+	})
+
+	tests := []struct {
+		name         string
+		path         string
+		maxRedirects int
+	}{
+		{
+			name:         "redirect loop",
+			path:         "/redirect-loop",
+			maxRedirects: 10,
+		},
+		{
+			name:         "redirects disabled",
+			path:         "/redirect/test-63-w3cmicrodata-relative-url.html",
+			maxRedirects: 0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := New().SetMaxRedirects(test.maxRedirects).Extract(server.URL+test.path, nil)
+			var redirectsErr *TooManyRedirectsError
+			if !errors.As(err, &redirectsErr) {
+				t.Fatalf("expected *TooManyRedirectsError, got %v", err)
+			}
+			if redirectsErr.MaxRedirects != test.maxRedirects {
+				t.Errorf("expected max redirects %d, got %d", test.maxRedirects, redirectsErr.MaxRedirects)
+			}
+		})
+	}
+}
+
+func TestExtractor_Response(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	tests := []struct {
+		name             string
+		url              string
+		content          *string
+		wantStatus       int
+		wantLastModified string
+	}{
+		{
+			name:             "fetched content",
+			url:              fmt.Sprintf("%s/test-01-opengraph-minimal.html", server.URL),
+			content:          nil,
+			wantStatus:       http.StatusOK,
+			wantLastModified: "Wed, 01 Jan 2025 00:00:00 GMT",
+		},
+		{
+			name:             "not found",
+			url:              fmt.Sprintf("%s/404", server.URL),
+			content:          nil,
+			wantStatus:       http.StatusNotFound,
+			wantLastModified: "",
+		},
+		{
+			name:             "provided content",
+			url:              "https://example.com/",
+			content:          pointerOfString("<html></html>"),
+			wantStatus:       0,
+			wantLastModified: "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, _ := New().Extract(test.url, test.content)
+			if e.ResponseStatus() != test.wantStatus {
+				t.Errorf("expected status %d, got %d", test.wantStatus, e.ResponseStatus())
+			}
+			if got := e.ResponseHeader().Get("Last-Modified"); got != test.wantLastModified {
+				t.Errorf("expected Last-Modified %q, got %q", test.wantLastModified, got)
+			}
+			if test.content != nil && e.ResponseHeader() != nil {
+				t.Errorf("expected nil header, got %v", e.ResponseHeader())
+			}
+		})
+	}
+}
+
+func TestExtractor_fetch_contentEncoding(t *testing.T) {
+	const page = `<html><head><meta property="og:title" content="Compressed title"></head></html>`
+
+	compress := func(coding string) []byte {
+		var buf bytes.Buffer
+		var w io.WriteCloser
+		switch coding {
+		case "gzip":
+			w = gzip.NewWriter(&buf)
+		case "deflate":
+			w = zlib.NewWriter(&buf)
+		case "br":
+			w = brotli.NewWriter(&buf)
+		default:
+			return []byte(page)
+		}
+		_, _ = w.Write([]byte(page))
+		_ = w.Close()
+
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		coding  string
+		wantErr bool
+	}{
+		{coding: ""},
+		{coding: "gzip"},
+		{coding: "deflate"},
+		{coding: "br"},
+		{coding: "compress", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.coding, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if test.coding != "" {
+					w.Header().Set("Content-Encoding", test.coding)
+				}
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				_, _ = w.Write(compress(test.coding))
+			}))
+			defer server.Close()
+
+			e := New()
+			got, err := e.fetch(context.Background(), server.URL)
+			if test.wantErr {
+				var encodingError *UnsupportedContentEncodingError
+				if !errors.As(err, &encodingError) || encodingError.ContentEncoding != test.coding {
+					t.Fatalf("expected *UnsupportedContentEncodingError, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != page {
+				t.Errorf("expected %q, got %q", page, got)
+			}
+		})
+	}
+}
+
+func TestExtractor_Extract_fragment(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/test-01-opengraph-minimal.html#section", server.URL)
+	e, err := New().SetSyntaxes([]Syntax{SyntaxOpenGraph}).Extract(url, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.url != url {
+		t.Errorf("expected URL to be %s, got %s", url, e.url)
+	}
+	if e.FinalURL() != url {
+		t.Errorf("expected the final URL to keep the fragment, got %s", e.FinalURL())
+	}
+	if e.GetExtracted()[SyntaxOpenGraph] == nil {
+		t.Error("expected opengraph to be extracted")
+	}
+
+	redirected := fmt.Sprintf("%s/redirect/test-01-opengraph-minimal.html#section", server.URL)
+	e, err = New().SetSyntaxes([]Syntax{SyntaxOpenGraph}).Extract(redirected, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := fmt.Sprintf("%s/catalog/test-01-opengraph-minimal.html", server.URL); e.FinalURL() != want {
+		t.Errorf("expected the final URL to be the redirect target %s, got %s", want, e.FinalURL())
+	}
+}
+
+func Test_stripFragment(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{
+			name: "URL with fragment",
+			url:  "https://x.com/page#section",
+			want: "https://x.com/page",
+		},
+		{
+			name: "URL with query and fragment",
+			url:  "https://x.com/page?a=1#section",
+			want: "https://x.com/page?a=1",
+		},
+		{
+			name: "URL without fragment",
+			url:  "https://x.com/page",
+			want: "https://x.com/page",
+		},
+		{
+			name: "unparseable URL",
+			url:  "https://x.com/%zz#section",
+			want: "https://x.com/%zz#section",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := stripFragment(test.url); got != test.want {
+				t.Errorf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestExtractor_DeclaredCharset(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/test-46-html-charset.html", server.URL)
+	content, err := os.ReadFile("./test/test-46-html-charset.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		content *string
+		want    string
+	}{
+		{
+			name:    "charset of the Content-Type header",
+			content: nil,
+			want:    "utf-8",
+		},
+		{
+			name:    "charset of the meta element",
+			content: pointerOfString(string(content)),
+			want:    "iso-8859-1",
+		},
+		{
+			name:    "no charset declared",
+			content: pointerOfString("<html><head><title>no charset</title></head></html>"),
+			want:    "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := New().Extract(url, test.content)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := e.DeclaredCharset(); got != test.want {
+				t.Errorf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestExtractor_Extract_charset(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	metaContent, err := os.ReadFile("./test/test-61-html-charset-meta.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		url             string
+		content         *string
+		wantTitle       string
+		wantDescription string
+	}{
+		{
+			name:            "charset of the Content-Type header",
+			url:             fmt.Sprintf("%s/test-62-html-charset-header.html?content-type=%s", server.URL, url.QueryEscape("text/html; charset=windows-1252")),
+			content:         nil,
+			wantTitle:       "Café – menu",
+			wantDescription: "Crêpes, galettes et cidre",
+		},
+		{
+			name:            "charset of the meta element of fetched content",
+			url:             fmt.Sprintf("%s/test-61-html-charset-meta.html?content-type=%s", server.URL, url.QueryEscape("text/html")),
+			content:         nil,
+			wantTitle:       "Café crème",
+			wantDescription: "Déjeuner à la française",
+		},
+		{
+			name:            "charset of the meta element of provided content",
+			url:             "https://example.com/cafe",
+			content:         pointerOfString(string(metaContent)),
+			wantTitle:       "Café crème",
+			wantDescription: "Déjeuner à la française",
+		},
+		{
+			name:            "provided UTF-8 content declaring another charset",
+			url:             "https://example.com/cafe",
+			content:         pointerOfString(`<meta charset="ISO-8859-1"><meta property="og:title" content="Café crème"><meta property="og:description" content="Déjeuner">`),
+			wantTitle:       "Café crème",
+			wantDescription: "Déjeuner",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := New().SetSyntaxes([]Syntax{SyntaxOpenGraph}).Extract(test.url, test.content)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(e.errs) > 0 {
+				t.Errorf("unexpected errors: %v", e.errs)
+			}
+			og, ok := e.GetExtracted()[SyntaxOpenGraph].(*extract.OpenGraph)
+			if !ok {
+				t.Fatalf("expected *extract.OpenGraph, got %T", e.GetExtracted()[SyntaxOpenGraph])
+			}
+			if og.Title != test.wantTitle {
+				t.Errorf("expected title %q, got %q", test.wantTitle, og.Title)
+			}
+			if og.Description != test.wantDescription {
+				t.Errorf("expected description %q, got %q", test.wantDescription, og.Description)
+			}
+		})
+	}
+}
+
+func TestExtractor_Links(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/test-40-html-links.html", server.URL)
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	tests := []struct {
+		name     string
+		syntaxes []Syntax
+	}{
+		{
+			name:     "html syntax extracted",
+			syntaxes: []Syntax{SyntaxHTML},
+		},
+		{
+			name:     "html syntax parsed on demand",
+			syntaxes: []Syntax{SyntaxOpenGraph},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := New().SetSyntaxes(test.syntaxes).Extract(url, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			links := e.Links()
+			if len(links) != 11 {
+				t.Fatalf("expected 11 links, got %d", len(links))
+			}
+			want := extract.HTMLLink{Rel: "canonical", Href: fmt.Sprintf("%s/page/canonical.html", server.URL)}
+			if links[0] != want {
+				t.Errorf("expected %+v, got %+v", want, links[0])
+			}
+			want = extract.HTMLLink{Rel: "amphtml", Href: fmt.Sprintf("https://%s/amp/page.html", host)}
+			if links[1] != want {
+				t.Errorf("expected %+v, got %+v", want, links[1])
+			}
+
+			_, extracted := e.GetExtracted()[SyntaxHTML]
+			if extracted != contains(test.syntaxes, SyntaxHTML) {
+				t.Errorf("expected html syntax extracted to be %v", contains(test.syntaxes, SyntaxHTML))
+			}
+		})
+	}
+}
+
 func TestExtractor_GetExtracted(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -1885,6 +3912,127 @@ func TestExtractor_GetExtracted(t *testing.T) {
 	}
 }
 
+func TestExtractor_GetErrors(t *testing.T) {
+	errJSONLD := errors.New("invalid JSON-LD")
+	tests := []struct {
+		name  string
+		setup func() *Extractor
+		want  []error
+	}{
+		{
+			name: "errors recorded",
+			setup: func() *Extractor {
+				return &Extractor{
+					errs: []error{errJSONLD},
+				}
+			},
+			want: []error{errJSONLD},
+		},
+		{
+			name: "no errors",
+			setup: func() *Extractor {
+				return &Extractor{}
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := tt.setup()
+			got := e.GetErrors()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Extractor.GetErrors() = %v, want %v", got, tt.want)
+			}
+			if len(got) > 0 {
+				got[0] = nil
+				if e.errs[0] == nil {
+					t.Error("expected a copy of the errors")
+				}
+			}
+		})
+	}
+}
+
+func TestExtractor_syntaxAccessors(t *testing.T) {
+	content := `<html><head>
+<meta property="og:title" content="OG title">
+<meta name="twitter:title" content="X title">
+<script type="application/ld+json">{"@context": "https://schema.org", "@type": "WebPage", "name": "JSON-LD name"}</script>
+</head><body>
+<div itemscope itemtype="https://schema.org/Thing"><span itemprop="name">Microdata name</span></div>
+</body></html>`
+
+	e, err := New().Extract("https://example.com/", &content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if og := e.OpenGraph(); og == nil || og.Title != "OG title" {
+		t.Errorf("expected the Open Graph title, got %+v", og)
+	}
+	if xc := e.XCards(); xc == nil || xc.Title != "X title" {
+		t.Errorf("expected the X Cards title, got %+v", xc)
+	}
+	if nodes := e.JSONLD(); len(nodes) != 1 || nodes[0]["name"] != "JSON-LD name" {
+		t.Errorf("expected the JSON-LD node, got %v", nodes)
+	}
+	if items := e.Microdata(); len(items) != 1 || items[0].Properties["name"] != "Microdata name" {
+		t.Errorf("expected the microdata item, got %v", items)
+	}
+
+	empty, err := New().SetSyntaxes([]Syntax{SyntaxJSONLD}).Extract("https://example.com/", &content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if og := empty.OpenGraph(); og != nil {
+		t.Errorf("expected nil Open Graph, got %+v", og)
+	}
+	if xc := empty.XCards(); xc != nil {
+		t.Errorf("expected nil X Cards, got %+v", xc)
+	}
+	if items := empty.Microdata(); items != nil {
+		t.Errorf("expected nil microdata, got %v", items)
+	}
+
+	merged, err := New().SetMergeSocial(true).Extract("https://example.com/", &content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if og := merged.OpenGraph(); og != nil {
+		t.Errorf("expected nil Open Graph once merged, got %+v", og)
+	}
+}
+
+func TestExtractor_FoundSyntaxes(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	e, err := New().Extract(fmt.Sprintf("%s/test-29-ldjson-object.html", server.URL), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := e.FoundSyntaxes(), []Syntax{SyntaxJSONLD}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	content := `<html><head>
+<meta property="og:title" content="OG title">
+<script type="application/ld+json">{"@context": "https://schema.org", "@type": "WebPage", "name": "JSON-LD name"}</script>
+</head><body>
+<div itemscope itemtype="https://schema.org/Thing"><span itemprop="name">Microdata name</span></div>
+</body></html>`
+	e, err = New().SetSyntaxes([]Syntax{SyntaxMicrodata, SyntaxJSONLD, SyntaxXCards, SyntaxOpenGraph}).SetXCardsMergeOpenGraph(false).Extract("https://example.com/", &content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := e.FoundSyntaxes(), []Syntax{SyntaxOpenGraph, SyntaxJSONLD, SyntaxMicrodata}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if got := New().FoundSyntaxes(); got != nil {
+		t.Errorf("expected no syntaxes before extracting, got %v", got)
+	}
+}
+
 func TestExtractor_GetExtractedJSON(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,7 @@
+// Package extractor implements the parsers of the metadata syntaxes extracted by the root extract package: Open Graph,
+// X Cards, JSON-LD, W3C microdata, the HTML metadata and oEmbed. Every file of the extractors directory declares
+// package extractor, which does not match the directory name, so the package is best imported with an explicit
+// name, like the root package does:
+//
+//	import extractor "github.com/aafeher/go-microdata-extract/extractors"
+package extractor
@@ -0,0 +1,426 @@
+package extractor
+
+import (
+	"golang.org/x/net/html"
+	"io"
+	"mime"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// HTMLMeta represents the standard metadata of an HTML document
+type HTMLMeta struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Favicon     string `json:"favicon,omitempty"`
+
+	Charset   string     `json:"charset,omitempty"`
+	Canonical string     `json:"canonical,omitempty"`
+	AMPHTML   string     `json:"amphtml,omitempty"`
+	AMP       bool       `json:"amp,omitempty"`
+	Next      string     `json:"next,omitempty"`
+	Prev      string     `json:"prev,omitempty"`
+	Feeds     []HTMLLink `json:"feeds,omitempty"`
+	Icons     []HTMLLink `json:"icons,omitempty"`
+	Links     []HTMLLink `json:"links,omitempty"`
+
+	// origins hinted by rel="preconnect" and rel="dns-prefetch" links
+	Preconnect  []string `json:"preconnect,omitempty"`
+	DNSPrefetch []string `json:"dns_prefetch,omitempty"`
+
+	// values of <meta http-equiv> elements
+	ContentLanguage string       `json:"content_language,omitempty"`
+	ContentType     string       `json:"content_type,omitempty"`
+	Refresh         *HTMLRefresh `json:"refresh,omitempty"`
+}
+
+// HTMLRefresh represents a <meta http-equiv="refresh"> element: the page reloads, or redirects to URL if given,
+// after Delay seconds
+type HTMLRefresh struct {
+	Delay int    `json:"delay"`
+	URL   string `json:"url,omitempty"`
+}
+
+// HTMLLink represents a <link> element of an HTML document
+type HTMLLink struct {
+	Rel      string `json:"rel"`
+	Href     string `json:"href"`
+	Type     string `json:"type,omitempty"`
+	Hreflang string `json:"hreflang,omitempty"`
+	Sizes    string `json:"sizes,omitempty"`
+}
+
+// HasRel reports whether the space-separated rel attribute of the link contains rel, case-insensitively.
+func (l HTMLLink) HasRel(rel string) bool {
+	for _, token := range strings.Fields(l.Rel) {
+		if strings.EqualFold(token, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewHTMLMeta creates a new HTMLMeta instance with basic initialization
+func NewHTMLMeta() *HTMLMeta {
+	return &HTMLMeta{}
+}
+
+func ParseHTMLMeta(URL string, htmlContent string) (any, []error) {
+	item, errors := extractHTMLMeta(URL, htmlContent)
+
+	var results any
+	if item != nil {
+		results = item
+	}
+
+	return results, errors
+}
+
+func extractHTMLMeta(URL string, htmlContent string) (*HTMLMeta, []error) {
+	var errors []error
+
+	hm := NewHTMLMeta()
+	baseURL := documentBaseURL(URL, htmlContent)
+	tokenizer := html.NewTokenizer(strings.NewReader(htmlContent))
+
+	// pagination given on anchors, used when there is no <link> for it
+	var anchorNext, anchorPrev string
+
+	// the title of the document is its first <title> outside <svg> elements, which have their own titles
+	inTitle := false
+	svgDepth := 0
+
+	hmHasValue := false
+	for {
+		if tokenizer.Err() == io.EOF {
+			break
+		}
+		tokenType := tokenizer.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			if tokenizer.Err() == io.EOF {
+				break
+			}
+			errors = append(errors, tokenizer.Err())
+		case html.TextToken:
+			if inTitle && hm.Title == "" {
+				hm.Title = strings.Join(strings.Fields(string(tokenizer.Text())), " ")
+				hmHasValue = hmHasValue || hm.Title != ""
+			}
+		case html.EndTagToken:
+			switch name, _ := tokenizer.TagName(); string(name) {
+			case "title":
+				inTitle = false
+			case "svg":
+				if svgDepth > 0 {
+					svgDepth--
+				}
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			if token.Data == "svg" && tokenType == html.StartTagToken {
+				svgDepth++
+				continue
+			}
+			if token.Data == "title" {
+				inTitle = tokenType == html.StartTagToken && svgDepth == 0
+				continue
+			}
+			if token.Data == "html" {
+				// an AMP document declares itself with <html ⚡> or <html amp>
+				for _, attr := range token.Attr {
+					if attr.Key == "⚡" || attr.Key == "amp" {
+						hm.AMP = true
+						hmHasValue = true
+					}
+				}
+				continue
+			}
+			if token.Data == "a" {
+				link := htmlLinkFromToken(baseURL, token)
+				if link.HasRel("next") && anchorNext == "" && link.Href != "" {
+					anchorNext = link.Href
+					hmHasValue = true
+				}
+				if (link.HasRel("prev") || link.HasRel("previous")) && anchorPrev == "" && link.Href != "" {
+					anchorPrev = link.Href
+					hmHasValue = true
+				}
+				continue
+			}
+			if token.Data == "meta" {
+				if charset := metaCharset(token); charset != "" && hm.Charset == "" {
+					hm.Charset = charset
+					hmHasValue = true
+				}
+				if parseHTTPEquiv(hm, baseURL, token) {
+					hmHasValue = true
+				}
+				if description := metaDescription(token); description != "" && hm.Description == "" {
+					hm.Description = description
+					hmHasValue = true
+				}
+				continue
+			}
+			if token.Data != "link" {
+				continue
+			}
+
+			link := htmlLinkFromToken(baseURL, token)
+			if link.Rel != "" && link.Href != "" {
+				hm.Links = append(hm.Links, link)
+				hmHasValue = true
+			}
+		default:
+			continue
+		}
+	}
+
+	if !hmHasValue {
+		return nil, errors
+	}
+
+	fillHTMLMetaFromLinks(hm)
+	if hm.Next == "" {
+		hm.Next = anchorNext
+	}
+	if hm.Prev == "" {
+		hm.Prev = anchorPrev
+	}
+
+	return hm, errors
+}
+
+// metaCharset returns the lowercased charset declared by a <meta charset> or a
+// <meta http-equiv="Content-Type"> token, or "" if the token declares none.
+func metaCharset(token html.Token) string {
+	var charset, httpEquiv, content string
+	for _, attr := range token.Attr {
+		switch attr.Key {
+		case "charset":
+			charset = attr.Val
+		case "http-equiv":
+			httpEquiv = attr.Val
+		case "content":
+			content = attr.Val
+		}
+	}
+	if charset == "" && strings.EqualFold(strings.TrimSpace(httpEquiv), "content-type") {
+		if _, params, err := mime.ParseMediaType(content); err == nil {
+			charset = params["charset"]
+		}
+	}
+
+	return strings.ToLower(strings.TrimSpace(charset))
+}
+
+// metaDescription returns the content of a <meta name="description"> token, or "" if the token is not one.
+func metaDescription(token html.Token) string {
+	var name, content string
+	for _, attr := range token.Attr {
+		switch attr.Key {
+		case "name":
+			name = attr.Val
+		case "content":
+			content = attr.Val
+		}
+	}
+	if !strings.EqualFold(strings.TrimSpace(name), "description") {
+		return ""
+	}
+
+	return strings.TrimSpace(content)
+}
+
+// parseHTTPEquiv fills the http-equiv fields of hm from a <meta http-equiv> token. The first value of each field
+// is kept. It reports whether a field has been set.
+func parseHTTPEquiv(hm *HTMLMeta, URL string, token html.Token) bool {
+	var httpEquiv, content string
+	for _, attr := range token.Attr {
+		switch attr.Key {
+		case "http-equiv":
+			httpEquiv = strings.ToLower(strings.TrimSpace(attr.Val))
+		case "content":
+			content = strings.TrimSpace(attr.Val)
+		}
+	}
+	if content == "" {
+		return false
+	}
+
+	switch httpEquiv {
+	case "content-language":
+		if hm.ContentLanguage == "" {
+			hm.ContentLanguage = content
+			return true
+		}
+	case "content-type":
+		if hm.ContentType == "" {
+			hm.ContentType = content
+			return true
+		}
+	case "refresh":
+		if hm.Refresh == nil {
+			if refresh := parseRefresh(URL, content); refresh != nil {
+				hm.Refresh = refresh
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// parseRefresh parses the content of a <meta http-equiv="refresh"> element, like "5" or "0; url=/new-page", with the
+// URL resolved against the base URL of the document. It returns nil if the delay is not a number.
+func parseRefresh(URL string, content string) *HTMLRefresh {
+	delay, target := content, ""
+	if i := strings.IndexAny(content, ";,"); i >= 0 {
+		delay, target = content[:i], content[i+1:]
+	}
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(delay), 64)
+	if err != nil || seconds < 0 {
+		return nil
+	}
+
+	refresh := &HTMLRefresh{Delay: int(seconds)}
+	target = strings.TrimSpace(target)
+	if len(target) >= 4 && strings.EqualFold(target[:4], "url=") {
+		target = strings.TrimSpace(target[4:])
+	}
+	refresh.URL = resolveURL(URL, strings.Trim(target, `"'`))
+
+	return refresh
+}
+
+// htmlLinkFromToken returns the link described by the attributes of a <link> or <a> token, with its href resolved
+// against the base URL of the document.
+func htmlLinkFromToken(URL string, token html.Token) HTMLLink {
+	link := HTMLLink{}
+	for _, attr := range token.Attr {
+		switch attr.Key {
+		case "rel":
+			link.Rel = strings.Join(strings.Fields(strings.ToLower(attr.Val)), " ")
+		case "href":
+			link.Href = resolveURL(URL, strings.TrimSpace(attr.Val))
+		case "type":
+			link.Type = strings.TrimSpace(attr.Val)
+		case "hreflang":
+			link.Hreflang = strings.TrimSpace(attr.Val)
+		case "sizes":
+			link.Sizes = strings.TrimSpace(attr.Val)
+		}
+	}
+
+	return link
+}
+
+// fillHTMLMetaFromLinks fills the specific link relations of hm from its generic list of links.
+func fillHTMLMetaFromLinks(hm *HTMLMeta) {
+	for _, link := range hm.Links {
+		switch {
+		case link.HasRel("canonical"):
+			if hm.Canonical == "" {
+				hm.Canonical = link.Href
+			}
+		case link.HasRel("amphtml"):
+			if hm.AMPHTML == "" {
+				hm.AMPHTML = link.Href
+			}
+		case link.HasRel("next"):
+			if hm.Next == "" {
+				hm.Next = link.Href
+			}
+		case link.HasRel("prev") || link.HasRel("previous"):
+			if hm.Prev == "" {
+				hm.Prev = link.Href
+			}
+		case link.HasRel("alternate") && (link.Type == "application/rss+xml" || link.Type == "application/atom+xml"):
+			hm.Feeds = append(hm.Feeds, link)
+		case link.HasRel("icon") || link.HasRel("apple-touch-icon"):
+			hm.Icons = append(hm.Icons, link)
+			if hm.Favicon == "" && link.HasRel("icon") {
+				hm.Favicon = link.Href
+			}
+		}
+		// a link may hint both, like rel="preconnect dns-prefetch"
+		if link.HasRel("preconnect") {
+			hm.Preconnect = appendOrigin(hm.Preconnect, link.Href)
+		}
+		if link.HasRel("dns-prefetch") {
+			hm.DNSPrefetch = appendOrigin(hm.DNSPrefetch, link.Href)
+		}
+	}
+}
+
+// appendOrigin appends the origin of the resolved href, like "https://cdn.example.com", to origins if it is not
+// listed yet. A href without a host is ignored.
+func appendOrigin(origins []string, href string) []string {
+	u, err := url.Parse(href)
+	if err != nil || u.Host == "" {
+		return origins
+	}
+	origin := u.Host
+	if u.Scheme != "" {
+		origin = u.Scheme + "://" + u.Host
+	}
+	for _, o := range origins {
+		if o == origin {
+			return origins
+		}
+	}
+
+	return append(origins, origin)
+}
+
+// documentBaseURL returns the URL that the relative URLs of the HTML content resolve against, the href of its first
+// <base> element if any, or else the page URL.
+func documentBaseURL(URL string, htmlContent string) string {
+	tokenizer := html.NewTokenizer(strings.NewReader(htmlContent))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return URL
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			if token.Data != "base" {
+				continue
+			}
+			for _, attr := range token.Attr {
+				if attr.Key == "href" {
+					return baseHrefURL(URL, attr.Val)
+				}
+			}
+		}
+	}
+}
+
+// baseHrefURL returns the href of a <base> element resolved against the page URL, or the page URL if it does not
+// resolve to an absolute URL.
+func baseHrefURL(URL string, href string) string {
+	resolved := resolveURL(URL, strings.TrimSpace(href))
+	if u, err := url.Parse(resolved); err != nil || !u.IsAbs() {
+		return URL
+	}
+
+	return resolved
+}
+
+// resolveURL resolves ref against the base URL. The reference is returned unchanged if it is absolute, the base is
+// empty or either URL cannot be parsed.
+func resolveURL(base, ref string) string {
+	if base == "" || ref == "" {
+		return ref
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil || refURL.IsAbs() {
+		return ref
+	}
+
+	return baseURL.ResolveReference(refURL).String()
+}
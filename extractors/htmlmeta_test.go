@@ -0,0 +1,452 @@
+package extractor
+
+import (
+	"golang.org/x/net/html"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseHTMLMeta_links(t *testing.T) {
+	content, err := os.ReadFile("../test/test-40-html-links.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	feeds := []HTMLLink{
+		{Rel: "alternate", Href: "https://example.com/page/feed.xml", Type: "application/rss+xml"},
+		{Rel: "alternate", Href: "https://example.com/atom.xml", Type: "application/atom+xml"},
+	}
+	icons := []HTMLLink{
+		{Rel: "shortcut icon", Href: "https://example.com/favicon.ico"},
+		{Rel: "icon", Href: "https://example.com/icon-32.png", Type: "image/png", Sizes: "32x32"},
+		{Rel: "apple-touch-icon", Href: "https://example.com/apple-touch-icon.png", Sizes: "180x180"},
+	}
+	want := &HTMLMeta{
+		Title:     "Test 40 HTML links",
+		Favicon:   "https://example.com/favicon.ico",
+		Charset:   "utf-8",
+		Canonical: "https://example.com/page/canonical.html",
+		AMPHTML:   "https://HOST/amp/page.html",
+		Feeds:     feeds,
+		Icons:     icons,
+		Links: []HTMLLink{
+			{Rel: "canonical", Href: "https://example.com/page/canonical.html"},
+			{Rel: "amphtml", Href: "https://HOST/amp/page.html"},
+			{Rel: "alternate", Href: "https://example.com/hu/page.html", Hreflang: "hu"},
+			feeds[0],
+			feeds[1],
+			icons[0],
+			icons[1],
+			icons[2],
+			{Rel: "stylesheet", Href: "https://cdn.example.com/style.css"},
+			{Rel: "preload", Href: "https://example.com/font.woff2"},
+			{Rel: "author", Href: "mailto:info@example.com"},
+		},
+	}
+
+	got, errs := ParseHTMLMeta("https://example.com/page/index.html", string(content))
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestParseHTMLMeta_head(t *testing.T) {
+	content, err := os.ReadFile("../test/test-80-html-head.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	icons := []HTMLLink{
+		{Rel: "apple-touch-icon", Href: "https://example.com/apple-touch-icon.png"},
+		{Rel: "icon", Href: "https://example.com/favicon.png", Type: "image/png"},
+	}
+	want := &HTMLMeta{
+		Title:       "Test 80 HTML head",
+		Description: "A page without structured data.",
+		Favicon:     "https://example.com/favicon.png",
+		Charset:     "utf-8",
+		Canonical:   "https://example.com/page/head.html",
+		Icons:       icons,
+		Links: []HTMLLink{
+			{Rel: "canonical", Href: "https://example.com/page/head.html"},
+			icons[0],
+			icons[1],
+		},
+		Refresh: &HTMLRefresh{Delay: 300},
+	}
+
+	got, errs := ParseHTMLMeta("https://example.com/page/index.html", string(content))
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestParseHTMLMeta_noLinks(t *testing.T) {
+	got, errs := ParseHTMLMeta("https://example.com/", "<html><head><title>no links</title></head></html>")
+	if want := (&HTMLMeta{Title: "no links"}); !reflect.DeepEqual(got, want) || len(errs) > 0 {
+		t.Errorf("expected %+v and no errors, got %+v, %v", want, got, errs)
+	}
+}
+
+func TestParseHTMLMeta_svgTitle(t *testing.T) {
+	got, _ := ParseHTMLMeta("", "<body><svg><title>Icon</title></svg><title>Document</title></body>")
+	if want := (&HTMLMeta{Title: "Document"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestParseHTMLMeta_empty(t *testing.T) {
+	got, errs := ParseHTMLMeta("https://example.com/", "<html><head><title> </title></head><body></body></html>")
+	if got != nil || len(errs) > 0 {
+		t.Errorf("expected nil result and no errors, got %v, %v", got, errs)
+	}
+}
+
+func Test_resolveURL(t *testing.T) {
+	tests := []struct {
+		name string
+		base string
+		ref  string
+		want string
+	}{
+		{
+			name: "absolute path",
+			base: "https://example.com/a/b.html",
+			ref:  "/c.html",
+			want: "https://example.com/c.html",
+		},
+		{
+			name: "relative path",
+			base: "https://example.com/a/b.html",
+			ref:  "c.html",
+			want: "https://example.com/a/c.html",
+		},
+		{
+			name: "absolute URL",
+			base: "https://example.com/a/b.html",
+			ref:  "http://example.org/c.html",
+			want: "http://example.org/c.html",
+		},
+		{
+			name: "empty base",
+			base: "",
+			ref:  "/c.html",
+			want: "/c.html",
+		},
+		{
+			name: "unparseable reference",
+			base: "https://example.com/",
+			ref:  "%zz",
+			want: "%zz",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := resolveURL(test.base, test.ref); got != test.want {
+				t.Errorf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func Test_documentBaseURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		URL     string
+		content string
+		want    string
+	}{
+		{
+			name:    "no base",
+			URL:     "https://example.com/page",
+			content: `<html><head><title>Page</title></head></html>`,
+			want:    "https://example.com/page",
+		},
+		{
+			name:    "absolute base",
+			URL:     "https://example.com/page",
+			content: `<html><head><base href="https://cdn.example.com/"></head></html>`,
+			want:    "https://cdn.example.com/",
+		},
+		{
+			name:    "relative base",
+			URL:     "https://example.com/blog/page",
+			content: `<html><head><base href="/assets/"></head></html>`,
+			want:    "https://example.com/assets/",
+		},
+		{
+			name:    "relative base without page URL",
+			URL:     "",
+			content: `<html><head><base href="/assets/"></head></html>`,
+			want:    "",
+		},
+		{
+			name:    "first base with href",
+			URL:     "https://example.com/page",
+			content: `<html><head><base target="_blank"><base href="https://first.example.com/"><base href="https://second.example.com/"></head></html>`,
+			want:    "https://first.example.com/",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := documentBaseURL(test.URL, test.content); got != test.want {
+				t.Errorf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestParseHTMLMeta_pagination(t *testing.T) {
+	content, err := os.ReadFile("../test/test-44-html-pagination.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	want := &HTMLMeta{
+		Title:   "Test 44 HTML pagination",
+		Charset: "utf-8",
+		Next:    "https://example.com/list?page=3",
+		Prev:    "https://example.com/list?page=1",
+		Links: []HTMLLink{
+			{Rel: "next", Href: "https://example.com/list?page=3"},
+		},
+	}
+
+	got, errs := ParseHTMLMeta("https://example.com/list?page=2", string(content))
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestParseHTMLMeta_baseHref(t *testing.T) {
+	content, err := os.ReadFile("../test/test-101-html-base-href.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	feed := HTMLLink{Rel: "alternate", Href: "https://cdn.example.com/assets/feed.xml", Type: "application/rss+xml"}
+	icon := HTMLLink{Rel: "icon", Href: "https://cdn.example.com/assets/favicon.png"}
+	want := &HTMLMeta{
+		Title:     "Test 101 HTML base href",
+		Charset:   "utf-8",
+		Canonical: "https://cdn.example.com/page/canonical.html",
+		Favicon:   icon.Href,
+		Next:      "https://cdn.example.com/assets/?page=3",
+		Feeds:     []HTMLLink{feed},
+		Icons:     []HTMLLink{icon},
+		Refresh: &HTMLRefresh{
+			Delay: 30,
+			URL:   "https://cdn.example.com/assets/latest.html",
+		},
+		Links: []HTMLLink{
+			{Rel: "canonical", Href: "https://cdn.example.com/page/canonical.html"},
+			icon,
+			feed,
+		},
+	}
+
+	got, errs := ParseHTMLMeta("https://example.com/news/index.html", string(content))
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestParseHTMLMeta_httpEquiv(t *testing.T) {
+	content, err := os.ReadFile("../test/test-54-html-http-equiv.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	want := &HTMLMeta{
+		Title:           "Test 54 HTML http-equiv",
+		Charset:         "windows-1252",
+		ContentLanguage: "hu-HU",
+		ContentType:     "text/html; charset=windows-1252",
+		Refresh: &HTMLRefresh{
+			Delay: 30,
+			URL:   "https://example.com/news/latest.html",
+		},
+	}
+
+	got, errs := ParseHTMLMeta("https://example.com/news/index.html", string(content))
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestParseHTMLMeta_resourceHints(t *testing.T) {
+	content, err := os.ReadFile("../test/test-56-html-resource-hints.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	want := &HTMLMeta{
+		Title:   "Test 56 HTML resource hints",
+		Charset: "utf-8",
+		Links: []HTMLLink{
+			{Rel: "preconnect", Href: "https://fonts.gstatic.com"},
+			{Rel: "preconnect dns-prefetch", Href: "https://cdn.example.com/assets/"},
+			{Rel: "dns-prefetch", Href: "https://analytics.example.net"},
+			{Rel: "dns-prefetch", Href: "https://cdn.example.com"},
+		},
+		Preconnect:  []string{"https://fonts.gstatic.com", "https://cdn.example.com"},
+		DNSPrefetch: []string{"https://cdn.example.com", "https://analytics.example.net"},
+	}
+
+	got, errs := ParseHTMLMeta("https://example.com/", string(content))
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestParseHTMLMeta_amp(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		want    *HTMLMeta
+	}{
+		{
+			name:    "AMP document",
+			fixture: "test-57-html-amp-document.html",
+			want: &HTMLMeta{
+				Title:     "Test 57 HTML AMP document",
+				Charset:   "utf-8",
+				Canonical: "https://example.com/article.html",
+				AMP:       true,
+				Links: []HTMLLink{
+					{Rel: "canonical", Href: "https://example.com/article.html"},
+				},
+			},
+		},
+		{
+			name:    "canonical page linking to its AMP version",
+			fixture: "test-58-html-amp-canonical.html",
+			want: &HTMLMeta{
+				Title:     "Test 58 HTML AMP canonical",
+				Charset:   "utf-8",
+				Canonical: "https://example.com/article.html",
+				AMPHTML:   "https://example.com/amp/article.html",
+				Links: []HTMLLink{
+					{Rel: "canonical", Href: "https://example.com/article.html"},
+					{Rel: "amphtml", Href: "https://example.com/amp/article.html"},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			content, err := os.ReadFile("../test/" + test.fixture)
+			if err != nil {
+				t.Fatalf("reading fixture: %v", err)
+			}
+			got, errs := ParseHTMLMeta("https://example.com/article.html", string(content))
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %+v, got %+v", test.want, got)
+			}
+		})
+	}
+
+	t.Run("amp attribute", func(t *testing.T) {
+		got, _ := ParseHTMLMeta("", `<html amp><head></head></html>`)
+		if hm, ok := got.(*HTMLMeta); !ok || !hm.AMP {
+			t.Errorf("expected an AMP document, got %+v", got)
+		}
+	})
+}
+
+func Test_parseRefresh(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *HTMLRefresh
+	}{
+		{
+			name:    "reload",
+			content: "5",
+			want:    &HTMLRefresh{Delay: 5},
+		},
+		{
+			name:    "redirect",
+			content: "0; url=https://example.com/new",
+			want:    &HTMLRefresh{Delay: 0, URL: "https://example.com/new"},
+		},
+		{
+			name:    "relative redirect with comma",
+			content: "1.5, /new",
+			want:    &HTMLRefresh{Delay: 1, URL: "https://example.com/new"},
+		},
+		{
+			name:    "invalid delay",
+			content: "soon; url=/new",
+			want:    nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := parseRefresh("https://example.com/page", test.content); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %+v, got %+v", test.want, got)
+			}
+		})
+	}
+}
+
+func Test_metaCharset(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "meta charset",
+			content: `<meta charset="ISO-8859-1">`,
+			want:    "iso-8859-1",
+		},
+		{
+			name:    "meta http-equiv",
+			content: `<meta http-equiv="Content-Type" content="text/html; charset=windows-1252">`,
+			want:    "windows-1252",
+		},
+		{
+			name:    "meta http-equiv without charset",
+			content: `<meta http-equiv="Content-Type" content="text/html">`,
+			want:    "",
+		},
+		{
+			name:    "other meta",
+			content: `<meta name="description" content="charset=utf-8">`,
+			want:    "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tokenizer := html.NewTokenizer(strings.NewReader(test.content))
+			tokenizer.Next()
+			if got := metaCharset(tokenizer.Token()); got != test.want {
+				t.Errorf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
@@ -2,51 +2,520 @@ package extractor
 
 import (
 	"encoding/json"
-	"regexp"
+	"errors"
+	"fmt"
+	"golang.org/x/net/html"
+	"io"
+	"sort"
 	"strings"
 )
 
+// ErrJSONLDBlockTooLarge is wrapped by the error recorded for a JSON-LD block skipped because it exceeds
+// Options.JSONLDMaxBlockBytes.
+var ErrJSONLDBlockTooLarge = errors.New("json-ld block too large")
+
+// JSONLDBlock represents a JSON-LD node along with the position of the <script> element declaring it.
+type JSONLDBlock struct {
+	Node map[string]any `json:"node"`
+	// Offset is the byte offset of the <script> start tag in the HTML content.
+	Offset int `json:"offset"`
+	// Line is the 1-based line number of the <script> start tag.
+	Line int `json:"line"`
+}
+
 func JSONLD(URL string, htmlContent string) ([]map[string]any, []error) {
+	return JSONLDWithOptions(URL, htmlContent, Options{})
+}
+
+// JSONLDWithOptions extracts the JSON-LD nodes of the HTML content like JSONLD, using the given parser options.
+func JSONLDWithOptions(URL string, htmlContent string, opts Options) ([]map[string]any, []error) {
 	_ = URL
-	items, errors := extractJSONLD(htmlContent)
+	blocks, errors := extractJSONLD(htmlContent, opts)
 
 	var results []map[string]any
-	if len(items) >= 0 {
-		results = append(results, items...)
+	for _, block := range blocks {
+		results = append(results, block.Node)
 	}
 
 	return results, errors
 }
 
-func extractJSONLD(htmlContent string) ([]map[string]any, []error) {
-	re := regexp.MustCompile(`(?s)<script[^>]+type=["']application/ld\+json["'][^>]*>(.*?)</script>`)
+// JSONLDBlocksWithOptions extracts the JSON-LD nodes of the HTML content like JSONLDWithOptions, along with the
+// position of their <script> elements. The nodes are returned in document order.
+func JSONLDBlocksWithOptions(URL string, htmlContent string, opts Options) ([]JSONLDBlock, []error) {
+	_ = URL
 
-	matches := re.FindAllStringSubmatch(htmlContent, -1)
+	return extractJSONLD(htmlContent, opts)
+}
 
+func extractJSONLD(htmlContent string, opts Options) ([]JSONLDBlock, []error) {
 	var errors []error
-	var jsonLDs []map[string]any
-	for _, match := range matches {
-		if len(match) > 1 {
-			jsonLD := strings.TrimSpace(match[1])
-			if jsonLD != "" {
-				if jsonLD[0] == '[' {
-					var jsonData []map[string]any
-					if err := json.Unmarshal([]byte(jsonLD), &jsonData); err != nil {
-						errors = append(errors, err)
-					} else {
-						jsonLDs = append(jsonLDs, jsonData...)
-					}
-				} else if jsonLD[0] == '{' {
-					var jsonData map[string]any
-					if err := json.Unmarshal([]byte(jsonLD), &jsonData); err != nil {
-						errors = append(errors, err)
-					} else {
-						jsonLDs = append(jsonLDs, jsonData)
+	var jsonLDs []JSONLDBlock
+	parsedBlocks := 0
+	for _, script := range jsonLDScripts(htmlContent) {
+		if opts.JSONLDMaxBlocks > 0 && parsedBlocks >= opts.JSONLDMaxBlocks {
+			break
+		}
+		jsonLD := strings.TrimSpace(script.text)
+		if opts.JSONLDMaxBlockBytes > 0 && len(jsonLD) > opts.JSONLDMaxBlockBytes {
+			errors = append(errors, fmt.Errorf("%w: %d bytes exceeds the limit of %d bytes, skipped", ErrJSONLDBlockTooLarge, len(jsonLD), opts.JSONLDMaxBlockBytes))
+			continue
+		}
+		jsonLD = stripJSComments(stripCDATA(jsonLD))
+		if jsonLD != "" {
+			parsedBlocks++
+			nodes, err := decodeJSONLDBlock(jsonLD, opts)
+			if err != nil {
+				// entity-encoded blocks are only unescaped when they fail as is, not to alter valid JSON strings
+				if unescaped := html.UnescapeString(jsonLD); unescaped != jsonLD {
+					if unescapedNodes, unescapedErr := decodeJSONLDBlock(unescaped, opts); unescapedErr == nil {
+						nodes, err = unescapedNodes, nil
 					}
 				}
 			}
+			if err != nil {
+				errors = append(errors, err)
+			}
+			line := strings.Count(htmlContent[:script.offset], "\n") + 1
+			for _, node := range nodes {
+				jsonLDs = append(jsonLDs, JSONLDBlock{Node: normalizeJSONLDNode(node, opts), Offset: script.offset, Line: line})
+			}
 		}
 	}
 
 	return jsonLDs, errors
 }
+
+// JSONLDDocumentWithOptions extracts the JSON-LD nodes of a JSON-LD document, like the body of a response of the
+// application/ld+json media type, using the given parser options. The document is decoded like the block of a
+// <script> element.
+func JSONLDDocumentWithOptions(URL string, jsonContent string, opts Options) ([]map[string]any, []error) {
+	_ = URL
+
+	jsonLD := strings.TrimSpace(strings.TrimPrefix(jsonContent, "\uFEFF"))
+	if jsonLD == "" {
+		return nil, nil
+	}
+	nodes, err := decodeJSONLDBlock(jsonLD, opts)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var results []map[string]any
+	for _, node := range nodes {
+		results = append(results, normalizeJSONLDNode(node, opts))
+	}
+
+	return results, nil
+}
+
+// normalizeJSONLDNode applies the expansion and the @type normalization enabled by the options to a decoded node.
+func normalizeJSONLDNode(node map[string]any, opts Options) map[string]any {
+	if opts.jsonLDNormalize(opts.JSONLDExpand) {
+		node = expandJSONLDNode(node, jsonLDContext{})
+	}
+	if opts.jsonLDNormalize(opts.JSONLDNormalizeType) {
+		normalizeJSONLDTypes(node)
+	}
+
+	return node
+}
+
+// jsonLDScript represents the raw text of a JSON-LD <script> element and the byte offset of its start tag.
+type jsonLDScript struct {
+	text   string
+	offset int
+}
+
+// jsonLDScripts returns the <script type="application/ld+json"> elements of the HTML content, in document order.
+// The type is matched case-insensitively, ignoring its parameters.
+func jsonLDScripts(htmlContent string) []jsonLDScript {
+	var scripts []jsonLDScript
+
+	tokenizer := html.NewTokenizer(strings.NewReader(htmlContent))
+	offset, scriptOffset := 0, -1
+	for {
+		tokenType := tokenizer.Next()
+		tokenOffset := offset
+		offset += len(tokenizer.Raw())
+		switch tokenType {
+		case html.ErrorToken:
+			return scripts
+		case html.StartTagToken:
+			scriptOffset = -1
+			if token := tokenizer.Token(); token.Data == "script" && isJSONLDScriptType(token.Attr) {
+				scriptOffset = tokenOffset
+			}
+		case html.TextToken:
+			if scriptOffset >= 0 {
+				// the text of a script element is raw, entities are not unescaped by the tokenizer
+				scripts = append(scripts, jsonLDScript{text: string(tokenizer.Text()), offset: scriptOffset})
+			}
+			scriptOffset = -1
+		default:
+			scriptOffset = -1
+		}
+	}
+}
+
+// isJSONLDScriptType reports whether the type attribute of a script element is the JSON-LD media type.
+func isJSONLDScriptType(attrs []html.Attribute) bool {
+	for _, attr := range attrs {
+		if attr.Key == "type" {
+			mediaType, _, _ := strings.Cut(attr.Val, ";")
+			return strings.EqualFold(strings.TrimSpace(mediaType), "application/ld+json")
+		}
+	}
+
+	return false
+}
+
+// decodeJSONLDBlock decodes the nodes of a JSON-LD script block, an object, an array of objects or concatenated
+// objects. Blocks starting with another character are ignored.
+func decodeJSONLDBlock(jsonLD string, opts Options) ([]map[string]any, error) {
+	switch jsonLD[0] {
+	case '[':
+		var jsonData []map[string]any
+		if err := json.Unmarshal([]byte(jsonLD), &jsonData); err != nil {
+			return nil, err
+		}
+		if opts.jsonLDNormalize(opts.JSONLDPropagateContext) {
+			propagateContext(jsonData)
+		}
+		return flattenGraphs(jsonData, opts), nil
+	case '{':
+		var jsonData map[string]any
+		if err := json.Unmarshal([]byte(jsonLD), &jsonData); err != nil {
+			if concatenated, ok := decodeConcatenatedObjects(jsonLD); ok {
+				return flattenGraphs(concatenated, opts), nil
+			}
+			return nil, err
+		}
+		return flattenGraphs([]map[string]any{jsonData}, opts), nil
+	}
+
+	return nil, nil
+}
+
+// stripCDATA removes a CDATA section wrapping a JSON-LD script block, along with the // or /* */ comments that
+// usually hide its markers from scripts.
+func stripCDATA(jsonLD string) string {
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(jsonLD, "//"), "/*")
+	trimmed = strings.TrimSpace(trimmed)
+	if !strings.HasPrefix(trimmed, "<![CDATA[") {
+		return jsonLD
+	}
+	trimmed = strings.TrimPrefix(trimmed, "<![CDATA[")
+	trimmed = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(trimmed), "*/"))
+
+	end := strings.LastIndex(trimmed, "]]>")
+	if end < 0 {
+		return jsonLD
+	}
+	trimmed = strings.TrimSpace(trimmed[:end])
+	trimmed = strings.TrimSuffix(strings.TrimSuffix(trimmed, "//"), "/*")
+
+	return strings.TrimSpace(trimmed)
+}
+
+// stripJSComments removes the /* */ and // comments written before the first or after the last JSON value of a
+// JSON-LD script block, as injected by some tag managers. Comment markers inside the JSON, like the // of a URL
+// string, are left alone.
+func stripJSComments(jsonLD string) string {
+	jsonLD = stripLeadingJSComments(jsonLD)
+
+	end := strings.LastIndexAny(jsonLD, "}]")
+	if end >= 0 && stripLeadingJSComments(jsonLD[end+1:]) == "" {
+		jsonLD = jsonLD[:end+1]
+	}
+
+	return jsonLD
+}
+
+// stripLeadingJSComments removes the whitespace and the /* */ and // comments at the start of s. An unterminated
+// block comment is left in place.
+func stripLeadingJSComments(s string) string {
+	for {
+		s = strings.TrimSpace(s)
+		switch {
+		case strings.HasPrefix(s, "//"):
+			end := strings.IndexAny(s, "\r\n")
+			if end < 0 {
+				return ""
+			}
+			s = s[end:]
+		case strings.HasPrefix(s, "/*"):
+			end := strings.Index(s[2:], "*/")
+			if end < 0 {
+				return s
+			}
+			s = s[2+end+2:]
+		default:
+			return s
+		}
+	}
+}
+
+// propagateContext sets the @context of the first node on the following nodes that have no @context of their own.
+func propagateContext(nodes []map[string]any) {
+	if len(nodes) == 0 || nodes[0] == nil {
+		return
+	}
+	context, ok := nodes[0]["@context"]
+	if !ok {
+		return
+	}
+	for _, node := range nodes[1:] {
+		if node == nil {
+			continue
+		}
+		if _, ok := node["@context"]; !ok {
+			node["@context"] = context
+		}
+	}
+}
+
+// flattenGraphs replaces the nodes holding only a @graph array, and optionally a @context, with the objects of the
+// array, setting the @context on those without one of their own. Named graphs, with other keys like @id, are kept.
+// Nothing is flattened if Options.JSONLDKeepGraphs is set.
+func flattenGraphs(nodes []map[string]any, opts Options) []map[string]any {
+	if !opts.jsonLDNormalize(!opts.JSONLDKeepGraphs) {
+		return nodes
+	}
+
+	var flattened []map[string]any
+	for _, node := range nodes {
+		graph, ok := node["@graph"].([]any)
+		if !ok || !isGraphContainer(node) {
+			flattened = append(flattened, node)
+			continue
+		}
+		context, hasContext := node["@context"]
+		for _, member := range graph {
+			m, ok := member.(map[string]any)
+			if !ok {
+				continue
+			}
+			if _, ok := m["@context"]; !ok && hasContext {
+				m["@context"] = context
+			}
+			flattened = append(flattened, m)
+		}
+	}
+
+	return flattened
+}
+
+// normalizeJSONLDTypes collapses the @type arrays of a single type of the node and of its nested nodes into a string,
+// and sorts the other @type arrays of strings.
+func normalizeJSONLDTypes(v any) {
+	switch value := v.(type) {
+	case map[string]any:
+		if types, ok := value["@type"].([]any); ok {
+			if len(types) == 1 {
+				if t, ok := types[0].(string); ok {
+					value["@type"] = t
+				}
+			} else {
+				sortJSONLDTypes(types)
+			}
+		}
+		for key, nested := range value {
+			if key != "@type" {
+				normalizeJSONLDTypes(nested)
+			}
+		}
+	case []any:
+		for _, nested := range value {
+			normalizeJSONLDTypes(nested)
+		}
+	}
+}
+
+// sortJSONLDTypes sorts an array of types in place, unless it holds something other than strings.
+func sortJSONLDTypes(types []any) {
+	for _, t := range types {
+		if _, ok := t.(string); !ok {
+			return
+		}
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].(string) < types[j].(string)
+	})
+}
+
+// jsonLDContext represents the active context of a JSON-LD node, used to expand its keys: the vocabulary IRI that
+// terms are relative to, and the IRIs of the defined terms and prefixes.
+type jsonLDContext struct {
+	vocab string
+	terms map[string]string
+}
+
+// extend returns the active context updated with the @context of a node: a string, taken as the vocabulary IRI as
+// remote contexts are not fetched, an object with @vocab and term definitions, or an array of those.
+func (c jsonLDContext) extend(context any) jsonLDContext {
+	switch context := context.(type) {
+	case string:
+		c.vocab = vocabIRI(context)
+	case map[string]any:
+		terms := make(map[string]string, len(c.terms)+len(context))
+		for term, iri := range c.terms {
+			terms[term] = iri
+		}
+		if vocab, ok := context["@vocab"].(string); ok {
+			c.vocab = vocabIRI(vocab)
+		}
+		for term, definition := range context {
+			if strings.HasPrefix(term, "@") {
+				continue
+			}
+			switch definition := definition.(type) {
+			case string:
+				terms[term] = definition
+			case map[string]any:
+				if id, ok := definition["@id"].(string); ok {
+					terms[term] = id
+				}
+			}
+		}
+		c.terms = terms
+	case []any:
+		for _, item := range context {
+			c = c.extend(item)
+		}
+	}
+
+	return c
+}
+
+// expandIRI returns the full IRI of a key or a type: the IRI of a defined term, a compact IRI like "schema:author"
+// with its prefix expanded, or a term relative to the vocabulary. Keywords, absolute IRIs and terms that cannot be
+// expanded are returned as is.
+func (c jsonLDContext) expandIRI(value string) string {
+	if strings.HasPrefix(value, "@") || strings.Contains(value, "://") {
+		return value
+	}
+	if iri, ok := c.terms[value]; ok {
+		// the IRI of a term may be compact or relative to the vocabulary, but is not expanded as a term again
+		value = iri
+		if strings.HasPrefix(value, "@") || strings.Contains(value, "://") {
+			return value
+		}
+	}
+	if prefix, suffix, ok := strings.Cut(value, ":"); ok {
+		if iri, ok := c.terms[prefix]; ok {
+			return iri + suffix
+		}
+		return value
+	}
+	if c.vocab != "" {
+		return c.vocab + value
+	}
+
+	return value
+}
+
+// vocabIRI returns the IRI of a vocabulary, ending with a slash unless it ends with a slash or a hash already.
+func vocabIRI(iri string) string {
+	iri = strings.TrimSpace(iri)
+	if iri == "" || strings.HasSuffix(iri, "/") || strings.HasSuffix(iri, "#") {
+		return iri
+	}
+
+	return iri + "/"
+}
+
+// expandJSONLDNode returns a copy of the node with its keys and types expanded to full IRIs against its @context,
+// extending the context of its parent, and its nested nodes expanded as well. The @context is removed, as the keys
+// no longer depend on it.
+func expandJSONLDNode(node map[string]any, parent jsonLDContext) map[string]any {
+	context := parent
+	if nodeContext, ok := node["@context"]; ok {
+		context = parent.extend(nodeContext)
+	}
+
+	expanded := make(map[string]any, len(node))
+	for key, value := range node {
+		switch key {
+		case "@context":
+			continue
+		case "@type":
+			expanded[key] = expandJSONLDTypes(value, context)
+		default:
+			expanded[context.expandIRI(key)] = expandJSONLDValue(value, context)
+		}
+	}
+
+	return expanded
+}
+
+// expandJSONLDValue expands the nodes held by a value, a node or an array of values.
+func expandJSONLDValue(value any, context jsonLDContext) any {
+	switch value := value.(type) {
+	case map[string]any:
+		return expandJSONLDNode(value, context)
+	case []any:
+		values := make([]any, len(value))
+		for i, v := range value {
+			values[i] = expandJSONLDValue(v, context)
+		}
+		return values
+	default:
+		return value
+	}
+}
+
+// expandJSONLDTypes expands the @type of a node, a string or an array of strings.
+func expandJSONLDTypes(types any, context jsonLDContext) any {
+	switch types := types.(type) {
+	case string:
+		return context.expandIRI(types)
+	case []any:
+		expanded := make([]any, len(types))
+		for i, t := range types {
+			if s, ok := t.(string); ok {
+				expanded[i] = context.expandIRI(s)
+			} else {
+				expanded[i] = t
+			}
+		}
+		return expanded
+	default:
+		return types
+	}
+}
+
+// isGraphContainer reports whether the node has no keys other than @graph and @context.
+func isGraphContainer(node map[string]any) bool {
+	for key := range node {
+		if key != "@graph" && key != "@context" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// decodeConcatenatedObjects decodes JSON objects written one after the other without an enclosing array, like
+// `{...}{...}`, as emitted by some buggy templates. It reports false unless the whole input is a sequence of at least
+// two valid objects.
+func decodeConcatenatedObjects(jsonLD string) ([]map[string]any, bool) {
+	var objects []map[string]any
+
+	decoder := json.NewDecoder(strings.NewReader(jsonLD))
+	for {
+		var jsonData map[string]any
+		if err := decoder.Decode(&jsonData); err == io.EOF {
+			break
+		} else if err != nil || jsonData == nil {
+			return nil, false
+		}
+		objects = append(objects, jsonData)
+	}
+
+	if len(objects) < 2 {
+		return nil, false
+	}
+
+	return objects, true
+}
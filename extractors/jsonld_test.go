@@ -0,0 +1,739 @@
+package extractor
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONLDWithOptions_propagateContext(t *testing.T) {
+	content, err := os.ReadFile("../test/test-41-ldjson-array-context.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want []map[string]any
+	}{
+		{
+			name: "propagation disabled",
+			opts: Options{},
+			want: []map[string]any{
+				{"@context": "https://schema.org", "@type": "Organization", "name": "Example Organization"},
+				{"@type": "WebSite", "name": "Example Website"},
+				{"@context": "https://example.com/vocab", "@type": "Thing", "name": "Example Thing"},
+			},
+		},
+		{
+			name: "propagation enabled with passthrough",
+			opts: Options{JSONLDPropagateContext: true, JSONLDPassthrough: true},
+			want: []map[string]any{
+				{"@context": "https://schema.org", "@type": "Organization", "name": "Example Organization"},
+				{"@type": "WebSite", "name": "Example Website"},
+				{"@context": "https://example.com/vocab", "@type": "Thing", "name": "Example Thing"},
+			},
+		},
+		{
+			name: "propagation enabled",
+			opts: Options{JSONLDPropagateContext: true},
+			want: []map[string]any{
+				{"@context": "https://schema.org", "@type": "Organization", "name": "Example Organization"},
+				{"@context": "https://schema.org", "@type": "WebSite", "name": "Example Website"},
+				{"@context": "https://example.com/vocab", "@type": "Thing", "name": "Example Thing"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, errs := JSONLDWithOptions("", string(content), test.opts)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestJSONLDWithOptions_expand(t *testing.T) {
+	content, err := os.ReadFile("../test/test-88-ldjson-expand.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want []map[string]any
+	}{
+		{
+			name: "expansion disabled",
+			opts: Options{},
+			want: []map[string]any{
+				{
+					"@context": "https://schema.org",
+					"@type":    "Article",
+					"headline": "Expanded",
+					"author":   map[string]any{"@type": "Person", "name": "Jane Doe"},
+				},
+				{
+					"@context": map[string]any{
+						"@vocab": "https://schema.org/",
+						"ex":     "https://example.com/terms#",
+						"writer": "author",
+						"rating": map[string]any{"@id": "ex:rating", "@type": "@id"},
+					},
+					"@type":                        []any{"Review", "ex:Critique"},
+					"writer":                       "John Doe",
+					"rating":                       "5",
+					"ex:mood":                      "happy",
+					"https://example.com/terms#id": "r-1",
+				},
+			},
+		},
+		{
+			name: "expansion enabled with passthrough",
+			opts: Options{JSONLDExpand: true, JSONLDPassthrough: true},
+			want: []map[string]any{
+				{
+					"@context": "https://schema.org",
+					"@type":    "Article",
+					"headline": "Expanded",
+					"author":   map[string]any{"@type": "Person", "name": "Jane Doe"},
+				},
+				{
+					"@context": map[string]any{
+						"@vocab": "https://schema.org/",
+						"ex":     "https://example.com/terms#",
+						"writer": "author",
+						"rating": map[string]any{"@id": "ex:rating", "@type": "@id"},
+					},
+					"@type":                        []any{"Review", "ex:Critique"},
+					"writer":                       "John Doe",
+					"rating":                       "5",
+					"ex:mood":                      "happy",
+					"https://example.com/terms#id": "r-1",
+				},
+			},
+		},
+		{
+			name: "expansion enabled",
+			opts: Options{JSONLDExpand: true},
+			want: []map[string]any{
+				{
+					"@type":                       "https://schema.org/Article",
+					"https://schema.org/headline": "Expanded",
+					"https://schema.org/author": map[string]any{
+						"@type":                   "https://schema.org/Person",
+						"https://schema.org/name": "Jane Doe",
+					},
+				},
+				{
+					"@type":                            []any{"https://schema.org/Review", "https://example.com/terms#Critique"},
+					"https://schema.org/author":        "John Doe",
+					"https://example.com/terms#rating": "5",
+					"https://example.com/terms#mood":   "happy",
+					"https://example.com/terms#id":     "r-1",
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, errs := JSONLDWithOptions("", string(content), test.opts)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestJSONLDDocumentWithOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		opts    Options
+		want    []map[string]any
+		wantErr bool
+	}{
+		{
+			name:    "object",
+			content: `{"@context": "https://schema.org", "@type": "Product", "name": "Anvil"}`,
+			want:    []map[string]any{{"@context": "https://schema.org", "@type": "Product", "name": "Anvil"}},
+		},
+		{
+			name:    "array of objects",
+			content: "\n[{\"@type\": \"Product\"}, {\"@type\": \"Offer\"}]\n",
+			want:    []map[string]any{{"@type": "Product"}, {"@type": "Offer"}},
+		},
+		{
+			name:    "normalized type",
+			content: `{"@type": ["Product"]}`,
+			opts:    Options{JSONLDNormalizeType: true},
+			want:    []map[string]any{{"@type": "Product"}},
+		},
+		{
+			name:    "empty",
+			content: " ",
+		},
+		{
+			name:    "malformed",
+			content: `{"@type": "Product"`,
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, errs := JSONLDDocumentWithOptions("", test.content, test.opts)
+			if (len(errs) > 0) != test.wantErr {
+				t.Fatalf("expected errors: %v, got %v", test.wantErr, errs)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestJSONLDWithOptions_normalizeType(t *testing.T) {
+	content, err := os.ReadFile("../test/test-95-ldjson-type-array.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want []map[string]any
+	}{
+		{
+			name: "normalization disabled",
+			opts: Options{},
+			want: []map[string]any{
+				{"@context": "https://schema.org", "@type": []any{"Product"}, "name": "Anvil", "offers": map[string]any{"@type": []any{"Offer"}, "price": "119.99"}},
+				{"@context": "https://schema.org", "@type": []any{"Product", "IndividualProduct"}, "name": "Used anvil"},
+			},
+		},
+		{
+			name: "normalization enabled with passthrough",
+			opts: Options{JSONLDNormalizeType: true, JSONLDPassthrough: true},
+			want: []map[string]any{
+				{"@context": "https://schema.org", "@type": []any{"Product"}, "name": "Anvil", "offers": map[string]any{"@type": []any{"Offer"}, "price": "119.99"}},
+				{"@context": "https://schema.org", "@type": []any{"Product", "IndividualProduct"}, "name": "Used anvil"},
+			},
+		},
+		{
+			name: "normalization enabled",
+			opts: Options{JSONLDNormalizeType: true},
+			want: []map[string]any{
+				{"@context": "https://schema.org", "@type": "Product", "name": "Anvil", "offers": map[string]any{"@type": "Offer", "price": "119.99"}},
+				{"@context": "https://schema.org", "@type": []any{"IndividualProduct", "Product"}, "name": "Used anvil"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, errs := JSONLDWithOptions("", string(content), test.opts)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func Test_jsonLDContext_expandIRI(t *testing.T) {
+	context := jsonLDContext{}.extend([]any{
+		"http://schema.org",
+		map[string]any{"dc": "http://purl.org/dc/terms/", "title": "dc:title", "loop": "loop"},
+	})
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "@id", want: "@id"},
+		{value: "name", want: "http://schema.org/name"},
+		{value: "title", want: "http://purl.org/dc/terms/title"},
+		{value: "dc:creator", want: "http://purl.org/dc/terms/creator"},
+		{value: "unknown:term", want: "unknown:term"},
+		{value: "https://example.com/term", want: "https://example.com/term"},
+		{value: "loop", want: "http://schema.org/loop"},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			if got := context.expandIRI(test.value); got != test.want {
+				t.Errorf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestJSONLDWithOptions_graph(t *testing.T) {
+	content, err := os.ReadFile("../test/test-66-ldjson-graph.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	named := map[string]any{
+		"@context": "https://schema.org",
+		"@id":      "https://example.com/#named",
+		"@graph": []any{
+			map[string]any{"@type": "Thing", "name": "Named graph member"},
+		},
+	}
+	tests := []struct {
+		name string
+		opts Options
+		want []map[string]any
+	}{
+		{
+			name: "graph flattened",
+			opts: Options{},
+			want: []map[string]any{
+				{"@context": "https://schema.org", "@type": "Organization", "@id": "https://example.com/#organization", "name": "Example Organization"},
+				{"@context": "https://schema.org", "@type": "WebSite", "@id": "https://example.com/#website", "name": "Example Website", "publisher": map[string]any{"@id": "https://example.com/#organization"}},
+				{"@context": "https://example.com/vocab", "@type": "WebPage", "@id": "https://example.com/#webpage", "name": "Example Page"},
+				named,
+			},
+		},
+		{
+			name: "graph kept",
+			opts: Options{JSONLDKeepGraphs: true},
+			want: []map[string]any{
+				{
+					"@context": "https://schema.org",
+					"@graph": []any{
+						map[string]any{"@type": "Organization", "@id": "https://example.com/#organization", "name": "Example Organization"},
+						map[string]any{"@type": "WebSite", "@id": "https://example.com/#website", "name": "Example Website", "publisher": map[string]any{"@id": "https://example.com/#organization"}},
+						map[string]any{"@context": "https://example.com/vocab", "@type": "WebPage", "@id": "https://example.com/#webpage", "name": "Example Page"},
+					},
+				},
+				named,
+			},
+		},
+		{
+			name: "passthrough",
+			opts: Options{JSONLDPassthrough: true},
+			want: []map[string]any{
+				{
+					"@context": "https://schema.org",
+					"@graph": []any{
+						map[string]any{"@type": "Organization", "@id": "https://example.com/#organization", "name": "Example Organization"},
+						map[string]any{"@type": "WebSite", "@id": "https://example.com/#website", "name": "Example Website", "publisher": map[string]any{"@id": "https://example.com/#organization"}},
+						map[string]any{"@context": "https://example.com/vocab", "@type": "WebPage", "@id": "https://example.com/#webpage", "name": "Example Page"},
+					},
+				},
+				named,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, errs := JSONLDWithOptions("", string(content), test.opts)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestJSONLD_escapedBlocks(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		want    []map[string]any
+	}{
+		{
+			name:    "HTML entities",
+			fixture: "test-67-ldjson-entities.html",
+			want: []map[string]any{
+				{"@context": "https://schema.org", "@type": "Organization", "name": "Smith & Sons"},
+				{"@context": "https://schema.org", "@type": "WebSite", "name": "Tom &amp; Jerry", "url": "https://example.com/?a=1&amp;b=2"},
+			},
+		},
+		{
+			name:    "CDATA sections",
+			fixture: "test-68-ldjson-cdata.html",
+			want: []map[string]any{
+				{"@context": "https://schema.org", "@type": "Organization", "name": "Example Organization"},
+				{"@context": "https://schema.org", "@type": "WebSite", "name": "Example Website"},
+				{"@context": "https://schema.org", "@type": "WebPage", "name": "Example Page"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			content, err := os.ReadFile("../test/" + test.fixture)
+			if err != nil {
+				t.Fatalf("reading fixture: %v", err)
+			}
+			got, errs := JSONLD("", string(content))
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestJSONLD_scriptVariants(t *testing.T) {
+	content, err := os.ReadFile("../test/test-69-ldjson-script-variants.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	want := []map[string]any{
+		{"@context": "https://schema.org", "@type": "Organization", "name": "Example > Organization"},
+		{"@context": "https://schema.org", "@type": "WebSite", "name": "Example Website"},
+	}
+
+	got, errs := JSONLD("", string(content))
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestJSONLD_comments(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    []map[string]any
+	}{
+		{
+			fixture: "test-93-ldjson-block-comments.html",
+			want: []map[string]any{
+				{"@context": "https://schema.org", "@type": "Organization", "name": "Example Organization", "url": "https://example.com/about/*team*/"},
+			},
+		},
+		{
+			fixture: "test-94-ldjson-line-comments.html",
+			want: []map[string]any{
+				{"@context": "https://schema.org", "@type": "WebSite", "name": "Example Website", "url": "https://example.com/"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.fixture, func(t *testing.T) {
+			content, err := os.ReadFile("../test/" + test.fixture)
+			if err != nil {
+				t.Fatalf("reading fixture: %v", err)
+			}
+
+			got, errs := JSONLD("", string(content))
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestJSONLDBlocksWithOptions(t *testing.T) {
+	content, err := os.ReadFile("../test/test-51-ldjson-multiple-blocks.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	got, errs := JSONLDBlocksWithOptions("", string(content), Options{})
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	// the empty block is skipped, the nodes of an array share the position of their block
+	scripts := strings.SplitAfter(string(content), `<script type="application/ld+json">`)
+	offset := func(block int) int {
+		n := 0
+		for _, part := range scripts[:block] {
+			n += len(part)
+		}
+		return n - len(`<script type="application/ld+json">`)
+	}
+	wantPositions := []struct {
+		offset int
+		line   int
+		typ    string
+	}{
+		{offset: offset(1), line: 6, typ: "Product"},
+		{offset: offset(3), line: 15, typ: "Offer"},
+		{offset: offset(3), line: 15, typ: "Offer"},
+		{offset: offset(4), line: 29, typ: "Product"},
+	}
+	if len(got) != len(wantPositions) {
+		t.Fatalf("expected %d blocks, got %+v", len(wantPositions), got)
+	}
+	for i, want := range wantPositions {
+		if got[i].Offset != want.offset || got[i].Line != want.line || got[i].Node["@type"] != want.typ {
+			t.Errorf("block %d: expected %s at offset %d, line %d, got %v at offset %d, line %d", i, want.typ, want.offset, want.line, got[i].Node["@type"], got[i].Offset, got[i].Line)
+		}
+	}
+}
+
+func Test_stripCDATA(t *testing.T) {
+	tests := []struct {
+		name   string
+		jsonLD string
+		want   string
+	}{
+		{
+			name:   "no CDATA",
+			jsonLD: `{"name": "<![CDATA[x]]>"}`,
+			want:   `{"name": "<![CDATA[x]]>"}`,
+		},
+		{
+			name:   "bare CDATA",
+			jsonLD: "<![CDATA[ {\"name\": \"x\"} ]]>",
+			want:   `{"name": "x"}`,
+		},
+		{
+			name:   "line comments",
+			jsonLD: "//<![CDATA[\n{\"name\": \"x\"}\n//]]>",
+			want:   `{"name": "x"}`,
+		},
+		{
+			name:   "block comments",
+			jsonLD: "/*<![CDATA[*/\n{\"name\": \"x\"}\n/*]]>*/",
+			want:   `{"name": "x"}`,
+		},
+		{
+			name:   "unterminated CDATA",
+			jsonLD: `<![CDATA[ {"name": "x"}`,
+			want:   `<![CDATA[ {"name": "x"}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := stripCDATA(test.jsonLD); got != test.want {
+				t.Errorf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestJSONLD_concatenatedObjects(t *testing.T) {
+	content, err := os.ReadFile("../test/test-45-ldjson-concatenated-objects.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	want := []map[string]any{
+		{"@context": "https://schema.org", "@type": "Person", "name": "John Doe"},
+		{"@context": "https://schema.org", "@type": "Person", "name": "Jane Doe"},
+	}
+
+	got, errs := JSONLD("", string(content))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error for the truncated block, got %v", errs)
+	}
+	var syntaxError *json.SyntaxError
+	if !errors.As(errs[0], &syntaxError) {
+		t.Errorf("expected *json.SyntaxError, got %T", errs[0])
+	}
+}
+
+func Test_stripJSComments(t *testing.T) {
+	tests := []struct {
+		name   string
+		jsonLD string
+		want   string
+	}{
+		{
+			name:   "no comments",
+			jsonLD: `{"url": "https://example.com/"}`,
+			want:   `{"url": "https://example.com/"}`,
+		},
+		{
+			name:   "leading and trailing block comments",
+			jsonLD: "/* a */ /* b */\n{\"name\": \"x\"}\n/* c */",
+			want:   `{"name": "x"}`,
+		},
+		{
+			name:   "leading and trailing line comments",
+			jsonLD: "// a\r\n// b\n[{\"name\": \"x\"}]\n// c",
+			want:   `[{"name": "x"}]`,
+		},
+		{
+			name:   "comment markers inside strings",
+			jsonLD: "// a\n{\"url\": \"https://example.com/*x*/\"}",
+			want:   `{"url": "https://example.com/*x*/"}`,
+		},
+		{
+			name:   "unterminated block comment",
+			jsonLD: `/* a {"name": "x"}`,
+			want:   `/* a {"name": "x"}`,
+		},
+		{
+			name:   "trailing text",
+			jsonLD: `{"name": "x"} trailing`,
+			want:   `{"name": "x"} trailing`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := stripJSComments(test.jsonLD); got != test.want {
+				t.Errorf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func Test_decodeConcatenatedObjects(t *testing.T) {
+	tests := []struct {
+		name   string
+		jsonLD string
+		want   []map[string]any
+		wantOK bool
+	}{
+		{
+			name:   "two objects",
+			jsonLD: `{"name":"a"}{"name":"b"}`,
+			want:   []map[string]any{{"name": "a"}, {"name": "b"}},
+			wantOK: true,
+		},
+		{
+			name:   "two objects with whitespace",
+			jsonLD: "{\"name\":\"a\"}\n  {\"name\":\"b\"}\n",
+			want:   []map[string]any{{"name": "a"}, {"name": "b"}},
+			wantOK: true,
+		},
+		{
+			name:   "single object",
+			jsonLD: `{"name":"a"}`,
+			want:   nil,
+			wantOK: false,
+		},
+		{
+			name:   "trailing garbage",
+			jsonLD: `{"name":"a"}{"name":"b"}]`,
+			want:   nil,
+			wantOK: false,
+		},
+		{
+			name:   "object followed by array",
+			jsonLD: `{"name":"a"}[{"name":"b"}]`,
+			want:   nil,
+			wantOK: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := decodeConcatenatedObjects(test.jsonLD)
+			if ok != test.wantOK || !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %v, %v, got %v, %v", test.want, test.wantOK, got, ok)
+			}
+		})
+	}
+}
+
+func TestJSONLDWithOptions_maxBlockBytes(t *testing.T) {
+	small := `{"@context":"https://schema.org","@type":"Person","name":"John Doe"}`
+	oversized := `{"@context":"https://schema.org","@type":"ItemList","description":"` + strings.Repeat("x", 1024) + `"}`
+	content := `<html><body>
+<script type="application/ld+json">` + small + `</script>
+<script type="application/ld+json">` + oversized + `</script>
+</body></html>`
+
+	tests := []struct {
+		name      string
+		opts      Options
+		wantNodes int
+		wantErrs  int
+	}{
+		{
+			name:      "unlimited",
+			opts:      Options{},
+			wantNodes: 2,
+			wantErrs:  0,
+		},
+		{
+			name:      "limited",
+			opts:      Options{JSONLDMaxBlockBytes: 512},
+			wantNodes: 1,
+			wantErrs:  1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, errs := JSONLDWithOptions("", content, test.opts)
+			if len(got) != test.wantNodes {
+				t.Errorf("expected %d nodes, got %d", test.wantNodes, len(got))
+			}
+			if len(errs) != test.wantErrs {
+				t.Fatalf("expected %d errors, got %v", test.wantErrs, errs)
+			}
+			if test.wantErrs > 0 && !errors.Is(errs[0], ErrJSONLDBlockTooLarge) {
+				t.Errorf("expected ErrJSONLDBlockTooLarge, got %v", errs[0])
+			}
+			if len(got) > 0 && got[0]["name"] != "John Doe" {
+				t.Errorf("expected the small block to be kept, got %v", got[0])
+			}
+		})
+	}
+}
+
+func TestJSONLDWithOptions_maxBlocks(t *testing.T) {
+	content, err := os.ReadFile("../test/test-51-ldjson-multiple-blocks.html")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		maxBlocks int
+		wantTypes []string
+	}{
+		{
+			name:      "unlimited",
+			maxBlocks: 0,
+			wantTypes: []string{"Product", "Offer", "Offer", "Product"},
+		},
+		{
+			name:      "first block",
+			maxBlocks: 1,
+			wantTypes: []string{"Product"},
+		},
+		{
+			name:      "empty block not counted",
+			maxBlocks: 2,
+			wantTypes: []string{"Product", "Offer", "Offer"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, errs := JSONLDWithOptions("", string(content), Options{JSONLDMaxBlocks: test.maxBlocks})
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			var gotTypes []string
+			for _, node := range got {
+				gotTypes = append(gotTypes, JSONLDTypes(node)...)
+			}
+			if !reflect.DeepEqual(gotTypes, test.wantTypes) {
+				t.Errorf("expected %v, got %v", test.wantTypes, gotTypes)
+			}
+		})
+	}
+}
@@ -0,0 +1,501 @@
+package extractor
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MusicRecording represents a schema.org MusicRecording JSON-LD node
+type MusicRecording struct {
+	Name            string           `json:"name,omitempty"`
+	ByArtist        []string         `json:"byArtist,omitempty"`
+	Duration        string           `json:"duration,omitempty"`
+	InAlbum         string           `json:"inAlbum,omitempty"`
+	AggregateRating *AggregateRating `json:"aggregateRating,omitempty"`
+	Review          []Review         `json:"review,omitempty"`
+}
+
+// MusicAlbum represents a schema.org MusicAlbum JSON-LD node
+type MusicAlbum struct {
+	Name            string           `json:"name,omitempty"`
+	ByArtist        []string         `json:"byArtist,omitempty"`
+	NumTracks       int              `json:"numTracks,omitempty"`
+	Track           []MusicRecording `json:"track,omitempty"`
+	AggregateRating *AggregateRating `json:"aggregateRating,omitempty"`
+	Review          []Review         `json:"review,omitempty"`
+}
+
+// DecodeMusicRecording decodes a JSON-LD node of type MusicRecording. It returns nil if the node has another type.
+func DecodeMusicRecording(node map[string]any) *MusicRecording {
+	if !hasJSONLDType(node, "MusicRecording") {
+		return nil
+	}
+
+	return decodeMusicRecording(node)
+}
+
+// DecodeMusicAlbum decodes a JSON-LD node of type MusicAlbum. It returns nil if the node has another type.
+// The track list may be given directly or as an ItemList of ListItem elements.
+func DecodeMusicAlbum(node map[string]any) *MusicAlbum {
+	if !hasJSONLDType(node, "MusicAlbum") {
+		return nil
+	}
+
+	album := &MusicAlbum{
+		Name:            jsonLDString(node["name"]),
+		ByArtist:        jsonLDNames(node["byArtist"]),
+		NumTracks:       jsonLDInt(node["numTracks"]),
+		AggregateRating: decodeAggregateRating(node["aggregateRating"]),
+		Review:          decodeReviews(node["review"]),
+	}
+	for _, track := range jsonLDNodes(node["track"]) {
+		if hasJSONLDType(track, "ItemList") {
+			for _, element := range jsonLDNodes(track["itemListElement"]) {
+				if item, ok := element["item"].(map[string]any); ok {
+					element = item
+				}
+				album.Track = append(album.Track, *decodeMusicRecording(element))
+			}
+			continue
+		}
+		album.Track = append(album.Track, *decodeMusicRecording(track))
+	}
+
+	return album
+}
+
+func decodeMusicRecording(node map[string]any) *MusicRecording {
+	recording := &MusicRecording{
+		Name:            jsonLDString(node["name"]),
+		ByArtist:        jsonLDNames(node["byArtist"]),
+		Duration:        jsonLDString(node["duration"]),
+		AggregateRating: decodeAggregateRating(node["aggregateRating"]),
+		Review:          decodeReviews(node["review"]),
+	}
+	if names := jsonLDNames(node["inAlbum"]); len(names) > 0 {
+		recording.InAlbum = names[0]
+	}
+
+	return recording
+}
+
+// Product represents a schema.org Product JSON-LD node
+type Product struct {
+	Name            string           `json:"name,omitempty"`
+	Description     string           `json:"description,omitempty"`
+	Image           string           `json:"image,omitempty"`
+	SKU             string           `json:"sku,omitempty"`
+	Brand           string           `json:"brand,omitempty"`
+	AggregateRating *AggregateRating `json:"aggregateRating,omitempty"`
+	Review          []Review         `json:"review,omitempty"`
+}
+
+// DecodeProduct decodes a JSON-LD node of type Product. It returns nil if the node has another type.
+func DecodeProduct(node map[string]any) *Product {
+	if !hasJSONLDType(node, "Product") {
+		return nil
+	}
+
+	product := &Product{
+		Name:            jsonLDString(node["name"]),
+		Description:     jsonLDString(node["description"]),
+		Image:           jsonLDURL(node["image"]),
+		SKU:             jsonLDString(node["sku"]),
+		AggregateRating: decodeAggregateRating(node["aggregateRating"]),
+		Review:          decodeReviews(node["review"]),
+	}
+	if brands := jsonLDNames(node["brand"]); len(brands) > 0 {
+		product.Brand = brands[0]
+	}
+
+	return product
+}
+
+// AggregateRating represents a schema.org AggregateRating, shared by the types that can be rated
+type AggregateRating struct {
+	RatingValue float64 `json:"ratingValue,omitempty"`
+	ReviewCount int     `json:"reviewCount,omitempty"`
+	RatingCount int     `json:"ratingCount,omitempty"`
+	BestRating  float64 `json:"bestRating,omitempty"`
+	WorstRating float64 `json:"worstRating,omitempty"`
+}
+
+// Rating represents a schema.org Rating, like the rating given by a review
+type Rating struct {
+	RatingValue float64 `json:"ratingValue,omitempty"`
+	BestRating  float64 `json:"bestRating,omitempty"`
+	WorstRating float64 `json:"worstRating,omitempty"`
+}
+
+// Review represents a schema.org Review, shared by the types that can be reviewed
+type Review struct {
+	Author        string  `json:"author,omitempty"`
+	DatePublished string  `json:"datePublished,omitempty"`
+	ReviewBody    string  `json:"reviewBody,omitempty"`
+	ReviewRating  *Rating `json:"reviewRating,omitempty"`
+}
+
+// decodeAggregateRating decodes an aggregateRating object. It returns nil if there is none.
+func decodeAggregateRating(v any) *AggregateRating {
+	nodes := jsonLDNodes(v)
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	return &AggregateRating{
+		RatingValue: jsonLDFloat(nodes[0]["ratingValue"]),
+		ReviewCount: jsonLDInt(nodes[0]["reviewCount"]),
+		RatingCount: jsonLDInt(nodes[0]["ratingCount"]),
+		BestRating:  jsonLDFloat(nodes[0]["bestRating"]),
+		WorstRating: jsonLDFloat(nodes[0]["worstRating"]),
+	}
+}
+
+// decodeReviews decodes a review given as an object or an array of objects.
+func decodeReviews(v any) []Review {
+	var reviews []Review
+	for _, node := range jsonLDNodes(v) {
+		review := Review{
+			DatePublished: jsonLDString(node["datePublished"]),
+			ReviewBody:    jsonLDString(node["reviewBody"]),
+		}
+		if authors := jsonLDNames(node["author"]); len(authors) > 0 {
+			review.Author = authors[0]
+		}
+		if ratings := jsonLDNodes(node["reviewRating"]); len(ratings) > 0 {
+			review.ReviewRating = &Rating{
+				RatingValue: jsonLDFloat(ratings[0]["ratingValue"]),
+				BestRating:  jsonLDFloat(ratings[0]["bestRating"]),
+				WorstRating: jsonLDFloat(ratings[0]["worstRating"]),
+			}
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews
+}
+
+// Person represents a schema.org Person JSON-LD node
+type Person struct {
+	Name     string   `json:"name,omitempty"`
+	URL      string   `json:"url,omitempty"`
+	Image    string   `json:"image,omitempty"`
+	JobTitle string   `json:"jobTitle,omitempty"`
+	SameAs   []string `json:"sameAs,omitempty"`
+}
+
+// DecodePerson decodes a JSON-LD node of type Person. It returns nil if the node has another type.
+func DecodePerson(node map[string]any) *Person {
+	if !hasJSONLDType(node, "Person") {
+		return nil
+	}
+
+	return &Person{
+		Name:     jsonLDString(node["name"]),
+		URL:      jsonLDURL(node["url"]),
+		Image:    jsonLDURL(node["image"]),
+		JobTitle: jsonLDString(node["jobTitle"]),
+		SameAs:   jsonLDURLs(node["sameAs"]),
+	}
+}
+
+// Organization represents a schema.org Organization JSON-LD node
+type Organization struct {
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+	Logo string `json:"logo,omitempty"`
+}
+
+// WebSite represents a schema.org WebSite JSON-LD node
+type WebSite struct {
+	Name        string        `json:"name,omitempty"`
+	URL         string        `json:"url,omitempty"`
+	Description string        `json:"description,omitempty"`
+	InLanguage  string        `json:"inLanguage,omitempty"`
+	Publisher   *Organization `json:"publisher,omitempty"`
+}
+
+// WebPage represents a schema.org WebPage JSON-LD node
+type WebPage struct {
+	Name        string        `json:"name,omitempty"`
+	URL         string        `json:"url,omitempty"`
+	Description string        `json:"description,omitempty"`
+	InLanguage  string        `json:"inLanguage,omitempty"`
+	Publisher   *Organization `json:"publisher,omitempty"`
+}
+
+// webPageTypes lists WebPage and its schema.org subtypes.
+var webPageTypes = []string{
+	"WebPage", "AboutPage", "CheckoutPage", "CollectionPage", "ContactPage", "FAQPage", "ItemPage",
+	"MedicalWebPage", "ProfilePage", "QAPage", "RealEstateListing", "SearchResultsPage",
+}
+
+// DecodeWebSite decodes a JSON-LD node of type WebSite. It returns nil if the node has another type.
+func DecodeWebSite(node map[string]any) *WebSite {
+	if !hasJSONLDType(node, "WebSite") {
+		return nil
+	}
+
+	return &WebSite{
+		Name:        jsonLDString(node["name"]),
+		URL:         jsonLDString(node["url"]),
+		Description: jsonLDString(node["description"]),
+		InLanguage:  jsonLDLanguage(node["inLanguage"]),
+		Publisher:   decodeOrganization(node["publisher"]),
+	}
+}
+
+// DecodeWebPage decodes a JSON-LD node of type WebPage or one of its subtypes, like ItemPage or FAQPage. It returns
+// nil if the node has another type.
+func DecodeWebPage(node map[string]any) *WebPage {
+	isWebPage := false
+	for _, t := range webPageTypes {
+		if hasJSONLDType(node, t) {
+			isWebPage = true
+			break
+		}
+	}
+	if !isWebPage {
+		return nil
+	}
+
+	return &WebPage{
+		Name:        jsonLDString(node["name"]),
+		URL:         jsonLDString(node["url"]),
+		Description: jsonLDString(node["description"]),
+		InLanguage:  jsonLDLanguage(node["inLanguage"]),
+		Publisher:   decodeOrganization(node["publisher"]),
+	}
+}
+
+// CreativeWork represents the common properties of a schema.org CreativeWork JSON-LD node, like an Article
+type CreativeWork struct {
+	Name          string        `json:"name,omitempty"`
+	Headline      string        `json:"headline,omitempty"`
+	Description   string        `json:"description,omitempty"`
+	Image         []string      `json:"image,omitempty"`
+	URL           string        `json:"url,omitempty"`
+	DatePublished time.Time     `json:"datePublished,omitempty"`
+	Publisher     *Organization `json:"publisher,omitempty"`
+}
+
+// DecodeCreativeWork decodes the CreativeWork properties of a JSON-LD node of any type, as most types share some of
+// them, like the name and image of a Product. It returns nil if the node has none of them.
+func DecodeCreativeWork(node map[string]any) *CreativeWork {
+	work := &CreativeWork{
+		Name:          jsonLDString(node["name"]),
+		Headline:      jsonLDString(node["headline"]),
+		Description:   jsonLDString(node["description"]),
+		Image:         jsonLDURLs(node["image"]),
+		URL:           jsonLDURL(node["url"]),
+		DatePublished: parseTimeSafely(jsonLDString(node["datePublished"])),
+		Publisher:     decodeOrganization(node["publisher"]),
+	}
+	if work.Name == "" && work.Headline == "" && work.Description == "" && len(work.Image) == 0 && work.URL == "" &&
+		work.DatePublished.IsZero() && work.Publisher == nil {
+		return nil
+	}
+
+	return work
+}
+
+// decodeOrganization decodes an organization given as a name or as an object. The first one is used from an array.
+func decodeOrganization(v any) *Organization {
+	switch value := v.(type) {
+	case string:
+		if value = strings.TrimSpace(value); value != "" {
+			return &Organization{Name: value}
+		}
+	case map[string]any:
+		organization := &Organization{
+			Name: jsonLDString(value["name"]),
+			URL:  jsonLDString(value["url"]),
+			Logo: jsonLDURL(value["logo"]),
+		}
+		if *organization != (Organization{}) {
+			return organization
+		}
+	case []any:
+		for _, item := range value {
+			if organization := decodeOrganization(item); organization != nil {
+				return organization
+			}
+		}
+	}
+
+	return nil
+}
+
+// jsonLDURL returns a URL given as a string or as an object with a url or contentUrl, like an ImageObject.
+func jsonLDURL(v any) string {
+	switch value := v.(type) {
+	case string:
+		return strings.TrimSpace(value)
+	case map[string]any:
+		if u := jsonLDString(value["url"]); u != "" {
+			return u
+		}
+		return jsonLDString(value["contentUrl"])
+	case []any:
+		for _, item := range value {
+			if u := jsonLDURL(item); u != "" {
+				return u
+			}
+		}
+	}
+
+	return ""
+}
+
+// jsonLDURLs normalizes a JSON-LD value given as a URL, an object with a url or an array of those into a list of
+// URLs.
+func jsonLDURLs(v any) []string {
+	var urls []string
+	switch value := v.(type) {
+	case []any:
+		for _, item := range value {
+			urls = append(urls, jsonLDURLs(item)...)
+		}
+	default:
+		if u := jsonLDURL(value); u != "" {
+			urls = append(urls, u)
+		}
+	}
+
+	return urls
+}
+
+// jsonLDLanguage returns a language given as a code or as a Language object with an alternateName or name.
+func jsonLDLanguage(v any) string {
+	switch value := v.(type) {
+	case string:
+		return strings.TrimSpace(value)
+	case map[string]any:
+		if language := jsonLDString(value["alternateName"]); language != "" {
+			return language
+		}
+		return jsonLDString(value["name"])
+	}
+
+	return ""
+}
+
+// hasJSONLDType reports whether the @type of node, given as a string or an array of strings, contains t.
+// A schema.org prefix on the type is ignored.
+func hasJSONLDType(node map[string]any, t string) bool {
+	for _, nodeType := range JSONLDTypes(node) {
+		if nodeType == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// JSONLDTypes returns the normalized types of a JSON-LD node, whose @type may be a string or an array of strings.
+func JSONLDTypes(node map[string]any) []string {
+	var types []string
+	switch v := node["@type"].(type) {
+	case string:
+		types = append(types, NormalizeSchemaType(v))
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				types = append(types, NormalizeSchemaType(s))
+			}
+		}
+	}
+
+	return types
+}
+
+// NormalizeSchemaType removes the schema.org vocabulary prefix from a type, so "https://schema.org/Product",
+// "http://schema.org/Product", "schema:Product" and "Product" are all normalized to "Product".
+// Types of other vocabularies are returned unchanged.
+func NormalizeSchemaType(t string) string {
+	t = strings.TrimSpace(t)
+	for _, prefix := range []string{"https://schema.org/", "http://schema.org/", "https://www.schema.org/", "http://www.schema.org/", "schema:"} {
+		if strings.HasPrefix(t, prefix) {
+			return strings.TrimPrefix(t, prefix)
+		}
+	}
+
+	return t
+}
+
+// jsonLDString returns the text of a JSON-LD value. Numbers are formatted, other non-string values yield "".
+func jsonLDString(v any) string {
+	switch value := v.(type) {
+	case string:
+		return strings.TrimSpace(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	return ""
+}
+
+// jsonLDInt returns the integer value of a JSON-LD number or numeric string.
+func jsonLDInt(v any) int {
+	switch value := v.(type) {
+	case float64:
+		return int(value)
+	case string:
+		return parseIntSafely(value)
+	}
+
+	return 0
+}
+
+// jsonLDFloat returns the value of a JSON-LD number or numeric string.
+func jsonLDFloat(v any) float64 {
+	switch value := v.(type) {
+	case float64:
+		return value
+	case string:
+		f, _ := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		return f
+	}
+
+	return 0
+}
+
+// jsonLDNames normalizes a JSON-LD value given as a string, an object with a name or an array of those into a
+// list of names.
+func jsonLDNames(v any) []string {
+	var names []string
+	switch value := v.(type) {
+	case string:
+		if value = strings.TrimSpace(value); value != "" {
+			names = append(names, value)
+		}
+	case map[string]any:
+		if name := jsonLDString(value["name"]); name != "" {
+			names = append(names, name)
+		}
+	case []any:
+		for _, item := range value {
+			names = append(names, jsonLDNames(item)...)
+		}
+	}
+
+	return names
+}
+
+// jsonLDNodes normalizes a JSON-LD value given as an object or an array of objects into a list of objects.
+func jsonLDNodes(v any) []map[string]any {
+	var nodes []map[string]any
+	switch value := v.(type) {
+	case map[string]any:
+		nodes = append(nodes, value)
+	case []any:
+		for _, item := range value {
+			if node, ok := item.(map[string]any); ok {
+				nodes = append(nodes, node)
+			}
+		}
+	}
+
+	return nodes
+}
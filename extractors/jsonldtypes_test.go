@@ -0,0 +1,275 @@
+package extractor
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecodeMusicAlbum(t *testing.T) {
+	nodes := jsonLDFixture(t, "test-39-ldjson-music-album.html")
+
+	want := &MusicAlbum{
+		Name:      "Greatest Hits II",
+		ByArtist:  []string{"Queen"},
+		NumTracks: 2,
+		Track: []MusicRecording{
+			{
+				Name:     "A Kind of Magic",
+				ByArtist: []string{"Queen"},
+				Duration: "PT4M22S",
+				InAlbum:  "Greatest Hits II",
+			},
+			{
+				Name:     "Under Pressure",
+				ByArtist: []string{"Queen", "David Bowie"},
+				Duration: "PT4M08S",
+				InAlbum:  "Greatest Hits II",
+			},
+		},
+	}
+
+	if got := DecodeMusicAlbum(nodes[0]); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if got := DecodeMusicRecording(nodes[0]); got != nil {
+		t.Errorf("expected nil for a MusicAlbum node, got %+v", got)
+	}
+}
+
+func TestDecodeMusicRecording(t *testing.T) {
+	tests := []struct {
+		name string
+		node map[string]any
+		want *MusicRecording
+	}{
+		{
+			name: "artist as object",
+			node: map[string]any{
+				"@type":    "https://schema.org/MusicRecording",
+				"name":     "Bohemian Rhapsody",
+				"byArtist": map[string]any{"@type": "MusicGroup", "name": "Queen"},
+				"duration": "PT5M55S",
+				"inAlbum":  map[string]any{"@type": "MusicAlbum", "name": "A Night at the Opera"},
+			},
+			want: &MusicRecording{
+				Name:     "Bohemian Rhapsody",
+				ByArtist: []string{"Queen"},
+				Duration: "PT5M55S",
+				InAlbum:  "A Night at the Opera",
+			},
+		},
+		{
+			name: "type as array",
+			node: map[string]any{
+				"@type": []any{"CreativeWork", "MusicRecording"},
+				"name":  "Innuendo",
+			},
+			want: &MusicRecording{
+				Name: "Innuendo",
+			},
+		},
+		{
+			name: "other type",
+			node: map[string]any{
+				"@type": "Person",
+				"name":  "Freddie Mercury",
+			},
+			want: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := DecodeMusicRecording(test.node); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %+v, got %+v", test.want, got)
+			}
+		})
+	}
+}
+
+// jsonLDFixture returns the JSON-LD nodes of a test fixture.
+func jsonLDFixture(t *testing.T, name string) []map[string]any {
+	t.Helper()
+
+	content, err := os.ReadFile("../test/" + name)
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+	nodes, errs := JSONLD("", string(content))
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	return nodes
+}
+
+func TestNormalizeSchemaType(t *testing.T) {
+	tests := []struct {
+		name string
+		t    string
+		want string
+	}{
+		{name: "https prefix", t: "https://schema.org/Product", want: "Product"},
+		{name: "http prefix", t: "http://schema.org/Product", want: "Product"},
+		{name: "compact prefix", t: "schema:Product", want: "Product"},
+		{name: "no prefix", t: "Product", want: "Product"},
+		{name: "other vocabulary", t: "https://example.com/Product", want: "https://example.com/Product"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := NormalizeSchemaType(test.t); got != test.want {
+				t.Errorf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestDecodeWebSite(t *testing.T) {
+	nodes := jsonLDFixture(t, "test-47-ldjson-website.html")
+
+	want := &WebSite{
+		Name:        "Example Website",
+		URL:         "https://example.com/",
+		Description: "An example website",
+		InLanguage:  "en-US",
+		Publisher: &Organization{
+			Name: "Example Organization",
+			URL:  "https://example.com/about",
+			Logo: "https://example.com/logo.png",
+		},
+	}
+
+	if got := DecodeWebSite(nodes[0]); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if got := DecodeWebSite(nodes[1]); got != nil {
+		t.Errorf("expected nil for an ItemPage node, got %+v", got)
+	}
+}
+
+func TestDecodeWebPage(t *testing.T) {
+	nodes := jsonLDFixture(t, "test-47-ldjson-website.html")
+
+	want := &WebPage{
+		Name:       "Example Page",
+		URL:        "https://example.com/page",
+		InLanguage: "en",
+		Publisher: &Organization{
+			Name: "Example Organization",
+		},
+	}
+
+	if got := DecodeWebPage(nodes[1]); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if got := DecodeWebPage(nodes[0]); got != nil {
+		t.Errorf("expected nil for a WebSite node, got %+v", got)
+	}
+}
+
+func TestDecodePerson(t *testing.T) {
+	nodes := jsonLDFixture(t, "test-29-ldjson-object.html")
+
+	want := &Person{
+		Name:     "Jane Doe",
+		URL:      "http://www.example.com",
+		Image:    "janedoe.jpg",
+		JobTitle: "Research Assistant",
+		SameAs: []string{
+			"https://www.facebook.com/",
+			"https://www.linkedin.com/",
+			"http://twitter.com/",
+			"http://instagram.com/",
+			"https://plus.google.com/",
+		},
+	}
+
+	if got := DecodePerson(nodes[0]); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if got := DecodePerson(map[string]any{"@type": "Organization"}); got != nil {
+		t.Errorf("expected nil for an Organization node, got %+v", got)
+	}
+}
+
+func TestDecodeCreativeWork(t *testing.T) {
+	node := map[string]any{
+		"@type":         "NewsArticle",
+		"headline":      "Breaking news",
+		"description":   "What happened",
+		"image":         []any{"https://example.com/a.jpg", map[string]any{"@type": "ImageObject", "url": "https://example.com/b.jpg"}},
+		"url":           "https://example.com/news/breaking",
+		"datePublished": "2025-01-02T03:04:05Z",
+		"publisher":     map[string]any{"@type": "Organization", "name": "Example News"},
+	}
+
+	want := &CreativeWork{
+		Headline:      "Breaking news",
+		Description:   "What happened",
+		Image:         []string{"https://example.com/a.jpg", "https://example.com/b.jpg"},
+		URL:           "https://example.com/news/breaking",
+		DatePublished: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Publisher:     &Organization{Name: "Example News"},
+	}
+
+	if got := DecodeCreativeWork(node); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if got := DecodeCreativeWork(map[string]any{"@type": "ListItem", "position": float64(1)}); got != nil {
+		t.Errorf("expected nil for a node without CreativeWork properties, got %+v", got)
+	}
+}
+
+func TestDecodeProduct(t *testing.T) {
+	nodes := jsonLDFixture(t, "test-59-ldjson-product-reviews.html")
+
+	want := &Product{
+		Name:        "Executive Anvil",
+		Description: "Sleeker than ACME's Classic Anvil, the Executive Anvil is perfect for the business traveler.",
+		Image:       "https://example.com/photos/1x1/photo.jpg",
+		SKU:         "0446310786",
+		Brand:       "ACME",
+		AggregateRating: &AggregateRating{
+			RatingValue: 4.4,
+			ReviewCount: 89,
+			BestRating:  5,
+		},
+		Review: []Review{
+			{
+				Author:        "Fred Benson",
+				DatePublished: "2024-04-01",
+				ReviewBody:    "Heavy and solid.",
+				ReviewRating:  &Rating{RatingValue: 4, BestRating: 5},
+			},
+			{
+				Author:       "Sara Smith",
+				ReviewBody:   "Too heavy to carry.",
+				ReviewRating: &Rating{RatingValue: 2},
+			},
+		},
+	}
+
+	if got := DecodeProduct(nodes[0]); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if got := DecodeProduct(map[string]any{"@type": "Person"}); got != nil {
+		t.Errorf("expected nil for a Person node, got %+v", got)
+	}
+}
+
+func Test_decodeReviews(t *testing.T) {
+	single := map[string]any{"@type": "Review", "author": "Sara Smith", "reviewBody": "Good."}
+
+	want := []Review{{Author: "Sara Smith", ReviewBody: "Good."}}
+	if got := decodeReviews(single); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v for a single review, got %+v", want, got)
+	}
+	if got := decodeReviews([]any{single}); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v for a review array, got %+v", want, got)
+	}
+	if got := decodeReviews(nil); got != nil {
+		t.Errorf("expected nil without reviews, got %+v", got)
+	}
+}
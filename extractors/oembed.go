@@ -0,0 +1,51 @@
+package extractor
+
+import (
+	"mime"
+	"strings"
+)
+
+// OEmbed represents the oEmbed endpoint advertised by an HTML document, and the oEmbed object fetched from it
+type OEmbed struct {
+	Endpoint string         `json:"endpoint"`
+	Data     map[string]any `json:"data,omitempty"`
+}
+
+// oEmbedJSONType is the media type of the links to JSON oEmbed endpoints.
+const oEmbedJSONType = "application/json+oembed"
+
+// NewOEmbed creates a new OEmbed instance with basic initialization
+func NewOEmbed() *OEmbed {
+	return &OEmbed{}
+}
+
+// ParseOEmbed discovers the JSON oEmbed endpoint of the HTML content, given by its first
+// <link rel="alternate" type="application/json+oembed"> element, resolved against the page URL. The oEmbed object is
+// not fetched, Data is left empty.
+func ParseOEmbed(URL string, htmlContent string) (any, []error) {
+	hm, errors := extractHTMLMeta(URL, htmlContent)
+	if hm == nil {
+		return nil, errors
+	}
+
+	for _, link := range hm.Links {
+		if link.HasRel("alternate") && isOEmbedJSONType(link.Type) {
+			oembed := NewOEmbed()
+			oembed.Endpoint = link.Href
+			return oembed, errors
+		}
+	}
+
+	return nil, errors
+}
+
+// isOEmbedJSONType reports whether the type attribute of a link is the JSON oEmbed media type, case-insensitively and
+// ignoring its parameters.
+func isOEmbedJSONType(linkType string) bool {
+	mediaType, _, err := mime.ParseMediaType(linkType)
+	if err != nil {
+		return strings.EqualFold(strings.TrimSpace(linkType), oEmbedJSONType)
+	}
+
+	return mediaType == oEmbedJSONType
+}
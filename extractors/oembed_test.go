@@ -0,0 +1,53 @@
+package extractor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseOEmbed(t *testing.T) {
+	tests := []struct {
+		name    string
+		URL     string
+		content string
+		want    any
+	}{
+		{
+			name:    "JSON endpoint",
+			URL:     "https://example.com/videos/1",
+			content: `<link rel="alternate" type="application/json+oembed" href="/oembed?url=https%3A%2F%2Fexample.com%2Fvideos%2F1">`,
+			want:    &OEmbed{Endpoint: "https://example.com/oembed?url=https%3A%2F%2Fexample.com%2Fvideos%2F1"},
+		},
+		{
+			name: "first JSON endpoint",
+			URL:  "https://example.com/videos/1",
+			content: `<link rel="alternate" type="text/xml+oembed" href="https://example.com/oembed.xml">
+<link rel="alternate" type="Application/JSON+oEmbed; charset=utf-8" href="https://example.com/oembed.json">
+<link rel="alternate" type="application/json+oembed" href="https://example.com/other.json">`,
+			want: &OEmbed{Endpoint: "https://example.com/oembed.json"},
+		},
+		{
+			name:    "not alternate",
+			URL:     "https://example.com/videos/1",
+			content: `<link rel="stylesheet" type="application/json+oembed" href="https://example.com/oembed.json">`,
+			want:    nil,
+		},
+		{
+			name:    "no link",
+			URL:     "https://example.com/videos/1",
+			content: `<title>No oEmbed</title>`,
+			want:    nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, errs := ParseOEmbed(test.URL, test.content)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %+v, got %+v", test.want, got)
+			}
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package extractor
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"golang.org/x/net/html"
 	"io"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -21,6 +24,10 @@ type OpenGraph struct {
 	LocaleAlternate []string `json:"og:locale:alternate,omitempty"`
 	SiteName        string   `json:"og:site_name,omitempty"`
 
+	// Freshness
+	UpdatedTime time.Time `json:"og:updated_time,omitempty"`
+	TTL         int       `json:"og:ttl,omitempty"`
+
 	// Media
 	OpenGraphImage []OpenGraphImage `json:"og:image,omitempty"`
 	OpenGraphVideo []OpenGraphVideo `json:"og:video,omitempty"`
@@ -40,6 +47,27 @@ type OpenGraph struct {
 
 	// Profile specific
 	Profile *Profile `json:"profile,omitempty"`
+
+	// Product specific
+	Product *OpenGraphProduct `json:"product,omitempty"`
+
+	// Extra holds the values of the properties not matching a known one, like fb:app_id, by property name, and the
+	// raw og:locale and og:locale:alternate values changed by the normalization.
+	Extra map[string][]string `json:"extra,omitempty"`
+}
+
+// MarshalJSON encodes the metadata like its fields would be, leaving out og:updated_time if it is the zero time,
+// which omitempty does not do for a time.Time.
+func (og OpenGraph) MarshalJSON() ([]byte, error) {
+	type openGraph OpenGraph
+	if !og.UpdatedTime.IsZero() {
+		return json.Marshal(openGraph(og))
+	}
+
+	return json.Marshal(struct {
+		openGraph
+		UpdatedTime *time.Time `json:"og:updated_time,omitempty"`
+	}{openGraph: openGraph(og)})
 }
 
 // OpenGraphImage represents OpenGraph image object
@@ -77,7 +105,7 @@ type Music struct {
 	Musician    []string    `json:"music:musician,omitempty"`
 	Song        []MusicSong `json:"music:song,omitempty"`
 	Creator     []string    `json:"music:creator,omitempty"`
-	ReleaseDate string      `json:"music:release_date,omitempty"`
+	ReleaseDate time.Time   `json:"music:release_date,omitempty"`
 }
 
 type MusicSong struct {
@@ -109,6 +137,15 @@ type Article struct {
 	Author         []string  `json:"article:author,omitempty"`
 	Section        string    `json:"article:section,omitempty"`
 	Tag            []string  `json:"article:tag,omitempty"`
+
+	// AuthorProfile lists the authors followed by profile: properties, which describe the author profile
+	AuthorProfile []ArticleAuthor `json:"article:author:profile,omitempty"`
+}
+
+// ArticleAuthor represents an article:author together with the profile: properties following it
+type ArticleAuthor struct {
+	URL string `json:"article:author"`
+	Profile
 }
 
 // Book represents book-specific metadata
@@ -127,14 +164,69 @@ type Profile struct {
 	Gender    string `json:"profile:gender,omitempty"`
 }
 
+// OpenGraphProduct represents product-specific metadata. The price amount is kept as a string to preserve its
+// precision.
+type OpenGraphProduct struct {
+	PriceAmount    string `json:"product:price:amount,omitempty"`
+	PriceCurrency  string `json:"product:price:currency,omitempty"`
+	Availability   string `json:"product:availability,omitempty"`
+	RetailerItemID string `json:"product:retailer_item_id,omitempty"`
+	Condition      string `json:"product:condition,omitempty"`
+}
+
 // NewOpenGraph creates a new OpenGraph instance with basic initialization
 func NewOpenGraph() *OpenGraph {
 	return &OpenGraph{}
 }
 
+// ErrInvalidOpenGraphValue is wrapped by the errors recorded for invalid Open Graph values when
+// Options.OpenGraphStrict is set.
+var ErrInvalidOpenGraphValue = errors.New("invalid open graph value")
+
+// ErrUnknownOpenGraphProperty is wrapped by the errors recorded for the properties of the Open Graph namespaces that
+// the protocol does not define, like og:colour, when Options.OpenGraphStrict is set. They are kept in Extra.
+var ErrUnknownOpenGraphProperty = errors.New("unknown open graph property")
+
+// ErrMissingOpenGraphProperty is wrapped by the errors recorded for the missing og:type, og:title or og:url when
+// Options.OpenGraphStrict is set, and by the errors returned by OpenGraph.Validate.
+var ErrMissingOpenGraphProperty = errors.New("missing open graph property")
+
+// openGraphDeterminers lists the valid values of og:determiner.
+var openGraphDeterminers = []string{"a", "an", "the", "", "auto"}
+
+// openGraphIntProperties lists the Open Graph properties holding an integer.
+var openGraphIntProperties = []string{
+	"og:ttl", "og:image:width", "og:image:height", "og:video:width", "og:video:height", "music:duration",
+	"music:album:disc", "music:album:track", "music:song:disc", "music:song:track", "video:duration",
+}
+
+// openGraphTimeProperties lists the Open Graph properties holding a date or a time.
+var openGraphTimeProperties = []string{
+	"og:updated_time", "music:release_date", "video:release_date", "article:published_time", "article:modified_time",
+	"article:expiration_time", "book:release_date",
+}
+
+// openGraphNamespaces lists the prefixes of the properties defined by the Open Graph protocol.
+var openGraphNamespaces = []string{"og:", "music:", "video:", "article:", "book:", "profile:"}
+
 func ParseOpenGraph(URL string, htmlContent string) (any, []error) {
-	_ = URL
-	item, errors := extractOpenGraph(htmlContent)
+	return ParseOpenGraphWithOptions(URL, htmlContent, Options{})
+}
+
+// ParseOpenGraphWithOptions extracts the Open Graph metadata of the HTML content like ParseOpenGraph, using the given
+// parser options.
+func ParseOpenGraphWithOptions(URL string, htmlContent string, opts Options) (any, []error) {
+	item, errors := extractOpenGraph(htmlContent, opts.OpenGraphStrict)
+	if item != nil {
+		resolveOpenGraphURLs(item, documentBaseURL(URL, htmlContent))
+	}
+	if item != nil && opts.OpenGraphDedupeMedia {
+		dedupeOpenGraphMedia(item)
+	}
+	if item != nil && opts.OpenGraphStrict {
+		errors = append(errors, validateOpenGraph(item)...)
+		errors = append(errors, missingOpenGraphProperties(item)...)
+	}
 
 	var results any
 	if item != nil {
@@ -144,13 +236,212 @@ func ParseOpenGraph(URL string, htmlContent string) (any, []error) {
 	return results, errors
 }
 
-func extractOpenGraph(htmlContent string) (*OpenGraph, []error) {
+// validateOpenGraph returns an error wrapping ErrInvalidOpenGraphValue for each value of og that the protocol does
+// not allow.
+func validateOpenGraph(og *OpenGraph) []error {
+	var errs []error
+
+	determiner := strings.ToLower(strings.TrimSpace(og.Determiner))
+	valid := false
+	for _, d := range openGraphDeterminers {
+		if determiner == d {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		errs = append(errs, fmt.Errorf("%w: og:determiner %q is not one of a, an, the, \"\" or auto", ErrInvalidOpenGraphValue, og.Determiner))
+	}
+
+	return errs
+}
+
+// missingOpenGraphProperties returns an error wrapping ErrMissingOpenGraphProperty for each of og:type, og:title and
+// og:url that og does not declare.
+func missingOpenGraphProperties(og *OpenGraph) []error {
+	var errs []error
+
+	for _, required := range []struct{ property, value string }{
+		{"og:type", og.Type},
+		{"og:title", og.Title},
+		{"og:url", og.URL},
+	} {
+		if strings.TrimSpace(required.value) == "" {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrMissingOpenGraphProperty, required.property))
+		}
+	}
+
+	return errs
+}
+
+// Validate returns an error wrapping ErrMissingOpenGraphProperty for each property required by the Open Graph
+// protocol that og does not declare: og:type, og:title, og:url and og:image.
+func (og *OpenGraph) Validate() []error {
+	errs := missingOpenGraphProperties(og)
+
+	hasImage := false
+	for _, image := range og.OpenGraphImage {
+		if strings.TrimSpace(image.URL) != "" {
+			hasImage = true
+			break
+		}
+	}
+	if !hasImage {
+		errs = append(errs, fmt.Errorf("%w: og:image", ErrMissingOpenGraphProperty))
+	}
+
+	return errs
+}
+
+// validateOpenGraphProperty returns an error wrapping ErrInvalidOpenGraphValue if the content of an integer, date or
+// time property cannot be parsed, which leaves its field as the zero value, or if a locale or an ISBN is not valid.
+func validateOpenGraphProperty(property, content string) error {
+	if property == "og:locale" || property == "og:locale:alternate" {
+		if _, ok := normalizeOpenGraphLocale(content); !ok {
+			return fmt.Errorf("%w: %s %q is not a locale", ErrInvalidOpenGraphValue, property, content)
+		}
+	}
+	if property == "book:isbn" {
+		if err := validateISBN(property, content); err != nil {
+			return err
+		}
+	}
+	if contains(openGraphIntProperties, property) {
+		if _, err := parseInt(content); err != nil {
+			return fmt.Errorf("%w: %s %q is not an integer", ErrInvalidOpenGraphValue, property, content)
+		}
+	}
+	if contains(openGraphTimeProperties, property) {
+		if _, err := parseTime(content); err != nil {
+			return fmt.Errorf("%w: %s %q is not a date or time", ErrInvalidOpenGraphValue, property, content)
+		}
+	}
+
+	return nil
+}
+
+// normalizeOpenGraphLocale returns the locale in the language_TERRITORY form of the Open Graph protocol, like en_US
+// for en-US, and whether it is a valid locale. A locale without a territory, like en, is valid and kept as is.
+func normalizeOpenGraphLocale(locale string) (string, bool) {
+	language, territory, hasTerritory := strings.Cut(strings.TrimSpace(locale), "-")
+	if !hasTerritory {
+		language, territory, hasTerritory = strings.Cut(language, "_")
+	}
+	if !isLetters(language, 2, 3) {
+		return locale, false
+	}
+	language = strings.ToLower(language)
+	if !hasTerritory {
+		return language, true
+	}
+	if !isLetters(territory, 2, 2) && !(len(territory) == 3 && isDigits(territory)) {
+		return locale, false
+	}
+
+	return language + "_" + strings.ToUpper(territory), true
+}
+
+// normalizeOpenGraphLocaleProperty returns the normalized og:locale or og:locale:alternate content, keeping the raw
+// content in the Extra of og when it is changed.
+func normalizeOpenGraphLocaleProperty(og *OpenGraph, property, content string) string {
+	normalized, _ := normalizeOpenGraphLocale(content)
+	if normalized != content {
+		if og.Extra == nil {
+			og.Extra = make(map[string][]string)
+		}
+		og.Extra[property] = append(og.Extra[property], content)
+	}
+
+	return normalized
+}
+
+// isLetters reports whether s consists of between min and max ASCII letters.
+func isLetters(s string, min, max int) bool {
+	if len(s) < min || len(s) > max {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// normalizeISBN returns the ISBN without its hyphens and spaces, like 9780306406157 for 978-0-306-40615-7, with the X
+// check digit of an ISBN-10 in upper case.
+func normalizeISBN(isbn string) string {
+	isbn = strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(isbn))
+
+	return strings.ToUpper(isbn)
+}
+
+// validateISBN returns an error wrapping ErrInvalidOpenGraphValue if the content of an ISBN property, like
+// book:isbn, is not a valid ISBN-10 or ISBN-13 once normalized.
+func validateISBN(property, content string) error {
+	if !isValidISBN(normalizeISBN(content)) {
+		return fmt.Errorf("%w: %s %q is not a valid ISBN-10 or ISBN-13", ErrInvalidOpenGraphValue, property, content)
+	}
+
+	return nil
+}
+
+// isValidISBN reports whether the normalized isbn is an ISBN-10 or an ISBN-13 with a valid check digit.
+func isValidISBN(isbn string) bool {
+	switch len(isbn) {
+	case 10:
+		sum := 0
+		for i, r := range isbn {
+			digit := int(r - '0')
+			switch {
+			case r == 'X' && i == 9:
+				digit = 10
+			case r < '0' || r > '9':
+				return false
+			}
+			sum += (10 - i) * digit
+		}
+		return sum%11 == 0
+	case 13:
+		if !isDigits(isbn) {
+			return false
+		}
+		sum := 0
+		for i, r := range isbn {
+			weight := 1
+			if i%2 == 1 {
+				weight = 3
+			}
+			sum += weight * int(r-'0')
+		}
+		return sum%10 == 0
+	}
+
+	return false
+}
+
+// isOpenGraphNamespace reports whether the property belongs to a namespace defined by the Open Graph protocol.
+func isOpenGraphNamespace(property string) bool {
+	for _, namespace := range openGraphNamespaces {
+		if strings.HasPrefix(property, namespace) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// extractOpenGraph returns the Open Graph metadata of the HTML content, or nil if it has none. If strict is set, an
+// error is recorded for each unparseable value and unknown property of the Open Graph namespaces.
+func extractOpenGraph(htmlContent string, strict bool) (*OpenGraph, []error) {
 	var errors []error
 
 	og := NewOpenGraph()
 	tokenizer := html.NewTokenizer(strings.NewReader(htmlContent))
 
 	ogHasValue := false
+	afterAuthor := false
 	for {
 		if tokenizer.Err() == io.EOF {
 			break
@@ -178,7 +469,28 @@ func extractOpenGraph(htmlContent string) (*OpenGraph, []error) {
 				}
 			}
 			if property != "" && content != "" {
-				parseOpenGraphMetaTag(og, property, content)
+				var known bool
+				if afterAuthor && strings.HasPrefix(property, "profile:") {
+					known = handleArticleAuthorProfileProperty(og.Article, property, content)
+				} else {
+					known = parseOpenGraphMetaTag(og, property, content)
+				}
+				// the profile: properties directly following an article:author describe that author
+				afterAuthor = property == "article:author" || (afterAuthor && strings.HasPrefix(property, "profile:"))
+				if !known {
+					if og.Extra == nil {
+						og.Extra = make(map[string][]string)
+					}
+					og.Extra[property] = append(og.Extra[property], content)
+				}
+				if strict && !known && isOpenGraphNamespace(property) {
+					errors = append(errors, fmt.Errorf("%w: %s %q", ErrUnknownOpenGraphProperty, property, content))
+				}
+				if strict && known {
+					if err := validateOpenGraphProperty(property, content); err != nil {
+						errors = append(errors, err)
+					}
+				}
 				ogHasValue = true
 			}
 		default:
@@ -193,7 +505,89 @@ func extractOpenGraph(htmlContent string) (*OpenGraph, []error) {
 	return nil, errors
 }
 
-func parseOpenGraphMetaTag(og *OpenGraph, property, content string) {
+// resolveOpenGraphURLs resolves the relative og:url and the relative URLs of the images, videos and audios of og
+// against the base URL of the document. Nothing is resolved without a base URL.
+func resolveOpenGraphURLs(og *OpenGraph, URL string) {
+	og.URL = resolveURL(URL, og.URL)
+	for i := range og.OpenGraphImage {
+		og.OpenGraphImage[i].URL = resolveURL(URL, og.OpenGraphImage[i].URL)
+		og.OpenGraphImage[i].SecureURL = resolveURL(URL, og.OpenGraphImage[i].SecureURL)
+	}
+	for i := range og.OpenGraphVideo {
+		og.OpenGraphVideo[i].URL = resolveURL(URL, og.OpenGraphVideo[i].URL)
+		og.OpenGraphVideo[i].SecureURL = resolveURL(URL, og.OpenGraphVideo[i].SecureURL)
+	}
+	for i := range og.OpenGraphAudio {
+		og.OpenGraphAudio[i].URL = resolveURL(URL, og.OpenGraphAudio[i].URL)
+		og.OpenGraphAudio[i].SecureURL = resolveURL(URL, og.OpenGraphAudio[i].SecureURL)
+	}
+}
+
+// dedupeOpenGraphMedia collapses the images, videos and audios of og with the same URL into their first occurrence,
+// filling its empty structured properties from the later ones. Elements without a URL are kept as is.
+func dedupeOpenGraphMedia(og *OpenGraph) {
+	og.OpenGraphImage = dedupeMedia(og.OpenGraphImage, func(image OpenGraphImage) string { return image.URL },
+		func(first *OpenGraphImage, image OpenGraphImage) {
+			first.SecureURL = firstNonEmpty(first.SecureURL, image.SecureURL)
+			first.Type = firstNonEmpty(first.Type, image.Type)
+			first.Width = firstNonZero(first.Width, image.Width)
+			first.Height = firstNonZero(first.Height, image.Height)
+			first.Alt = firstNonEmpty(first.Alt, image.Alt)
+		})
+	og.OpenGraphVideo = dedupeMedia(og.OpenGraphVideo, func(video OpenGraphVideo) string { return video.URL },
+		func(first *OpenGraphVideo, video OpenGraphVideo) {
+			first.SecureURL = firstNonEmpty(first.SecureURL, video.SecureURL)
+			first.Type = firstNonEmpty(first.Type, video.Type)
+			first.Width = firstNonZero(first.Width, video.Width)
+			first.Height = firstNonZero(first.Height, video.Height)
+		})
+	og.OpenGraphAudio = dedupeMedia(og.OpenGraphAudio, func(audio OpenGraphAudio) string { return audio.URL },
+		func(first *OpenGraphAudio, audio OpenGraphAudio) {
+			first.SecureURL = firstNonEmpty(first.SecureURL, audio.SecureURL)
+			first.Type = firstNonEmpty(first.Type, audio.Type)
+		})
+}
+
+// dedupeMedia returns the media elements with the same URL collapsed into their first occurrence, merging each later
+// one into it with merge. Elements without a URL are kept as is.
+func dedupeMedia[M any](media []M, url func(M) string, merge func(first *M, later M)) []M {
+	var deduped []M
+	indexes := make(map[string]int)
+	for _, element := range media {
+		u := url(element)
+		i, ok := indexes[u]
+		if !ok || u == "" {
+			indexes[u] = len(deduped)
+			deduped = append(deduped, element)
+			continue
+		}
+		merge(&deduped[i], element)
+	}
+
+	return deduped
+}
+
+// firstNonEmpty returns a unless it is empty, b otherwise.
+func firstNonEmpty(a, b string) string {
+	if a != "" {
+		return a
+	}
+
+	return b
+}
+
+// firstNonZero returns a unless it is 0, b otherwise.
+func firstNonZero(a, b int) int {
+	if a != 0 {
+		return a
+	}
+
+	return b
+}
+
+// parseOpenGraphMetaTag sets the field of og given by an Open Graph property. It reports false if the property does
+// not match a known one.
+func parseOpenGraphMetaTag(og *OpenGraph, property, content string) bool {
 	// Split property into parts to handle multi-level properties
 	parts := strings.Split(property, ":")
 
@@ -212,23 +606,27 @@ func parseOpenGraphMetaTag(og *OpenGraph, property, content string) {
 	case property == "og:determiner":
 		og.Determiner = content
 	case property == "og:locale":
-		og.Locale = content
+		og.Locale = normalizeOpenGraphLocaleProperty(og, property, content)
 	case property == "og:locale:alternate":
-		og.LocaleAlternate = append(og.LocaleAlternate, content)
+		og.LocaleAlternate = append(og.LocaleAlternate, normalizeOpenGraphLocaleProperty(og, property, content))
 	case property == "og:site_name":
 		og.SiteName = content
+	case property == "og:updated_time":
+		og.UpdatedTime = parseTimeSafely(content)
+	case property == "og:ttl":
+		og.TTL = parseIntSafely(content)
 
 	// Image handling with multi-level properties
 	case strings.HasPrefix(property, "og:image"):
-		handleOpenGraphImageProperty(og, parts, content)
+		return handleOpenGraphImageProperty(og, parts, content)
 
 	// Video handling with multi-level properties
 	case strings.HasPrefix(property, "og:video"):
-		handleOpenGraphVideoProperty(og, parts, content)
+		return handleOpenGraphVideoProperty(og, parts, content)
 
 	// Audio handling with multi-level properties
 	case strings.HasPrefix(property, "og:audio"):
-		handleOpenGraphAudioProperty(og, parts, content)
+		return handleOpenGraphAudioProperty(og, parts, content)
 
 	// Music handling with multi-level properties
 	case strings.HasPrefix(property, "music:"):
@@ -248,11 +646,13 @@ func parseOpenGraphMetaTag(og *OpenGraph, property, content string) {
 		case property == "music:musician":
 			og.Music.Musician = append(og.Music.Musician, content)
 		case strings.HasPrefix(property, "music:song"):
-			handleMusicSongProperty(og.Music, parts, content)
+			return handleMusicSongProperty(og.Music, parts, content)
 		case property == "music:release_date":
-			og.Music.ReleaseDate = content
+			og.Music.ReleaseDate = parseTimeSafely(content)
 		case property == "music:creator":
 			og.Music.Creator = append(og.Music.Creator, content)
+		default:
+			return false
 		}
 
 	// Video handling with multi-level properties
@@ -263,7 +663,7 @@ func parseOpenGraphMetaTag(og *OpenGraph, property, content string) {
 
 		switch {
 		case strings.HasPrefix(property, "video:actor"):
-			handleVideoActorProperty(og.Video, parts, content)
+			return handleVideoActorProperty(og.Video, parts, content)
 		case property == "video:director":
 			og.Video.Director = append(og.Video.Director, content)
 		case property == "video:writer":
@@ -276,6 +676,8 @@ func parseOpenGraphMetaTag(og *OpenGraph, property, content string) {
 			og.Video.Tag = append(og.Video.Tag, content)
 		case property == "video:series":
 			og.Video.Series = content
+		default:
+			return false
 		}
 
 	// Article handling remains the same
@@ -296,6 +698,8 @@ func parseOpenGraphMetaTag(og *OpenGraph, property, content string) {
 			og.Article.Section = content
 		case "article:tag":
 			og.Article.Tag = append(og.Article.Tag, content)
+		default:
+			return false
 		}
 
 	// Book handling remains the same
@@ -305,13 +709,15 @@ func parseOpenGraphMetaTag(og *OpenGraph, property, content string) {
 		}
 		switch property {
 		case "book:isbn":
-			og.Book.ISBN = content
+			og.Book.ISBN = normalizeISBN(content)
 		case "book:release_date":
 			og.Book.ReleaseDate = parseTimeSafely(content)
 		case "book:author":
 			og.Book.Author = append(og.Book.Author, content)
 		case "book:tag":
 			og.Book.Tag = append(og.Book.Tag, content)
+		default:
+			return false
 		}
 
 	// Profile handling remains the same
@@ -319,36 +725,100 @@ func parseOpenGraphMetaTag(og *OpenGraph, property, content string) {
 		if og.Profile == nil {
 			og.Profile = &Profile{}
 		}
-		switch property {
-		case "profile:first_name":
-			og.Profile.FirstName = content
-		case "profile:last_name":
-			og.Profile.LastName = content
-		case "profile:username":
-			og.Profile.Username = content
-		case "profile:gender":
-			og.Profile.Gender = content
+		if !setProfileProperty(og.Profile, property, content) {
+			return false
 		}
+
+	// Product handling
+	case strings.HasPrefix(property, "product:"):
+		if og.Product == nil {
+			og.Product = &OpenGraphProduct{}
+		}
+		if !setProductProperty(og.Product, property, content) {
+			return false
+		}
+
+	default:
+		return false
 	}
+
+	return true
 }
 
-func handleOpenGraphImageProperty(og *OpenGraph, parts []string, content string) {
-	if len(og.OpenGraphImage) == 0 {
-		og.OpenGraphImage = []OpenGraphImage{}
+// setProfileProperty sets the field of profile given by a profile: property. It reports false for an unknown property.
+func setProfileProperty(profile *Profile, property, content string) bool {
+	switch property {
+	case "profile:first_name":
+		profile.FirstName = content
+	case "profile:last_name":
+		profile.LastName = content
+	case "profile:username":
+		profile.Username = content
+	case "profile:gender":
+		profile.Gender = content
+	default:
+		return false
 	}
-	if len(og.OpenGraphImage) == 0 || parts[1] == "image" {
-		if len(parts) < 3 || len(og.OpenGraphImage) == 0 {
-			og.OpenGraphImage = append(og.OpenGraphImage, OpenGraphImage{})
-		}
+
+	return true
+}
+
+// setProductProperty sets the field of product given by a product: property. It reports false for an unknown property.
+func setProductProperty(product *OpenGraphProduct, property, content string) bool {
+	switch property {
+	case "product:price:amount":
+		product.PriceAmount = content
+	case "product:price:currency":
+		product.PriceCurrency = content
+	case "product:availability":
+		product.Availability = content
+	case "product:retailer_item_id":
+		product.RetailerItemID = content
+	case "product:condition":
+		product.Condition = content
+	default:
+		return false
+	}
+
+	return true
+}
+
+// handleArticleAuthorProfileProperty attaches a profile: property directly following an article:author to the
+// profile of that author, as such pages intend a nested author profile, instead of the profile of the page. It
+// reports false for an unknown property.
+func handleArticleAuthorProfileProperty(article *Article, property, content string) bool {
+	var profile Profile
+	if !setProfileProperty(&profile, property, content) {
+		return false
+	}
+	author := article.Author[len(article.Author)-1]
+	if len(article.AuthorProfile) == 0 || article.AuthorProfile[len(article.AuthorProfile)-1].URL != author {
+		article.AuthorProfile = append(article.AuthorProfile, ArticleAuthor{URL: author})
+	}
+	setProfileProperty(&article.AuthorProfile[len(article.AuthorProfile)-1].Profile, property, content)
+
+	return true
+}
+
+// handleOpenGraphImageProperty applies an og:image property, split into parts, to the structured image array, see
+// startsNewMediaElement for the rule splitting the array into elements. It reports false for an unknown property.
+func handleOpenGraphImageProperty(og *OpenGraph, parts []string, content string) bool {
+	if !isStructuredProperty(parts, "image", "url", "secure_url", "type", "width", "height", "alt") {
+		return false
+	}
+	if n := len(og.OpenGraphImage); n == 0 || startsNewMediaElement(parts, og.OpenGraphImage[n-1].URL, og.OpenGraphImage[n-1].SecureURL) {
+		og.OpenGraphImage = append(og.OpenGraphImage, OpenGraphImage{})
 	}
 	lastIdx := len(og.OpenGraphImage) - 1
 
 	if len(parts) == 2 {
 		og.OpenGraphImage[lastIdx].URL = content
-		return
+		return true
 	}
 
 	switch parts[2] {
+	case "url":
+		og.OpenGraphImage[lastIdx].URL = content
 	case "secure_url":
 		og.OpenGraphImage[lastIdx].SecureURL = content
 	case "type":
@@ -360,25 +830,29 @@ func handleOpenGraphImageProperty(og *OpenGraph, parts []string, content string)
 	case "alt":
 		og.OpenGraphImage[lastIdx].Alt = content
 	}
+
+	return true
 }
 
-func handleOpenGraphVideoProperty(og *OpenGraph, parts []string, content string) {
-	if len(og.OpenGraphVideo) == 0 {
-		og.OpenGraphVideo = []OpenGraphVideo{}
+// handleOpenGraphVideoProperty applies an og:video property to the structured video array, like
+// handleOpenGraphImageProperty. It reports false for an unknown property.
+func handleOpenGraphVideoProperty(og *OpenGraph, parts []string, content string) bool {
+	if !isStructuredProperty(parts, "video", "url", "secure_url", "type", "width", "height") {
+		return false
 	}
-	if len(og.OpenGraphVideo) == 0 || parts[1] == "video" {
-		if len(parts) < 3 || len(og.OpenGraphVideo) == 0 {
-			og.OpenGraphVideo = append(og.OpenGraphVideo, OpenGraphVideo{})
-		}
+	if n := len(og.OpenGraphVideo); n == 0 || startsNewMediaElement(parts, og.OpenGraphVideo[n-1].URL, og.OpenGraphVideo[n-1].SecureURL) {
+		og.OpenGraphVideo = append(og.OpenGraphVideo, OpenGraphVideo{})
 	}
 	lastIdx := len(og.OpenGraphVideo) - 1
 
 	if len(parts) == 2 {
 		og.OpenGraphVideo[lastIdx].URL = content
-		return
+		return true
 	}
 
 	switch parts[2] {
+	case "url":
+		og.OpenGraphVideo[lastIdx].URL = content
 	case "secure_url":
 		og.OpenGraphVideo[lastIdx].SecureURL = content
 	case "type":
@@ -388,33 +862,77 @@ func handleOpenGraphVideoProperty(og *OpenGraph, parts []string, content string)
 	case "height":
 		og.OpenGraphVideo[lastIdx].Height = parseIntSafely(content)
 	}
+
+	return true
 }
 
-func handleOpenGraphAudioProperty(og *OpenGraph, parts []string, content string) {
-	if len(og.OpenGraphAudio) == 0 {
-		og.OpenGraphAudio = []OpenGraphAudio{}
+// handleOpenGraphAudioProperty applies an og:audio property to the structured audio array, like
+// handleOpenGraphImageProperty. It reports false for an unknown property.
+func handleOpenGraphAudioProperty(og *OpenGraph, parts []string, content string) bool {
+	if !isStructuredProperty(parts, "audio", "url", "secure_url", "type") {
+		return false
 	}
-	if len(og.OpenGraphAudio) == 0 || parts[1] == "audio" {
-		if len(parts) < 3 || len(og.OpenGraphAudio) == 0 {
-			og.OpenGraphAudio = append(og.OpenGraphAudio, OpenGraphAudio{})
-		}
+	if n := len(og.OpenGraphAudio); n == 0 || startsNewMediaElement(parts, og.OpenGraphAudio[n-1].URL, og.OpenGraphAudio[n-1].SecureURL) {
+		og.OpenGraphAudio = append(og.OpenGraphAudio, OpenGraphAudio{})
 	}
 	lastIdx := len(og.OpenGraphAudio) - 1
 
 	if len(parts) == 2 {
 		og.OpenGraphAudio[lastIdx].URL = content
-		return
+		return true
 	}
 
 	switch parts[2] {
+	case "url":
+		og.OpenGraphAudio[lastIdx].URL = content
 	case "secure_url":
 		og.OpenGraphAudio[lastIdx].SecureURL = content
 	case "type":
 		og.OpenGraphAudio[lastIdx].Type = content
 	}
+
+	return true
 }
 
-func handleMusicSongProperty(music *Music, parts []string, content string) {
+// isStructuredProperty reports whether a property split into parts, like og:image:width, is the bare structured
+// property given by name or one of its known sub-properties.
+func isStructuredProperty(parts []string, name string, subProperties ...string) bool {
+	if len(parts) < 2 || len(parts) > 3 || parts[1] != name {
+		return false
+	}
+	if len(parts) == 2 {
+		return true
+	}
+	for _, sub := range subProperties {
+		if parts[2] == sub {
+			return true
+		}
+	}
+
+	return false
+}
+
+// startsNewMediaElement reports whether a property of a structured image, video or audio array, split into parts,
+// starts a new element after the last one, given its URL and SecureURL.
+//
+// A new element starts on the bare property (og:image, og:video, og:audio) or on its url synonym (og:image:url),
+// unless the last element was started by a sub-property and has no URL yet. The other structured sub-properties
+// (type, width, height, alt) always describe the most recent element, except a repeated secure_url, which starts a
+// new element, as an element cannot have two secure URLs.
+func startsNewMediaElement(parts []string, lastURL, lastSecureURL string) bool {
+	if len(parts) == 2 || parts[2] == "url" {
+		return lastURL != ""
+	}
+
+	return parts[2] == "secure_url" && lastSecureURL != ""
+}
+
+// handleMusicSongProperty applies a music:song property to the songs of music. It reports false for an unknown
+// property.
+func handleMusicSongProperty(music *Music, parts []string, content string) bool {
+	if !isStructuredProperty(parts, "song", "disc", "track") {
+		return false
+	}
 	if len(music.Song) == 0 || parts[1] == "song" {
 		if len(parts) < 3 {
 			music.Song = append(music.Song, MusicSong{})
@@ -424,7 +942,7 @@ func handleMusicSongProperty(music *Music, parts []string, content string) {
 
 	if len(parts) == 2 {
 		music.Song[lastIdx].URL = content
-		return
+		return true
 	}
 
 	switch parts[2] {
@@ -433,9 +951,16 @@ func handleMusicSongProperty(music *Music, parts []string, content string) {
 	case "track":
 		music.Song[lastIdx].Track = parseIntSafely(content)
 	}
+
+	return true
 }
 
-func handleVideoActorProperty(video *Video, parts []string, content string) {
+// handleVideoActorProperty applies a video:actor property to the actors of video. It reports false for an unknown
+// property.
+func handleVideoActorProperty(video *Video, parts []string, content string) bool {
+	if !isStructuredProperty(parts, "actor", "role") {
+		return false
+	}
 	if len(video.Actor) == 0 || parts[1] == "actor" {
 		if len(parts) < 3 {
 			video.Actor = append(video.Actor, VideoActor{})
@@ -445,37 +970,95 @@ func handleVideoActorProperty(video *Video, parts []string, content string) {
 
 	if len(parts) == 2 {
 		video.Actor[lastIdx].URL = content
-		return
+		return true
 	}
 
 	switch parts[2] {
 	case "role":
 		video.Actor[lastIdx].Role = content
 	}
+
+	return true
 }
 
 func parseIntSafely(s string) int {
-	var result int
-	_, err := fmt.Sscanf(s, "%d", &result)
-	if err != nil {
-		return 0
-	}
+	result, _ := parseInt(s)
+
 	return result
 }
 
+// parseInt parses an integer, dropping surrounding whitespace and thousands separators, like in "1,280". A decimal
+// with a zero fraction only, like "1200.0" templated by a CMS, is accepted, any other value is an error.
+func parseInt(s string) (int, error) {
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case ',', ' ', '\u00a0':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(s))
+
+	if i := strings.IndexByte(s, '.'); i >= 0 && i < len(s)-1 && strings.Trim(s[i+1:], "0") == "" {
+		s = s[:i]
+	}
+
+	return strconv.Atoi(s)
+}
+
 func parseTimeSafely(s string) time.Time {
+	t, _ := parseTime(s)
+
+	return t
+}
+
+// parseTime parses a date or a time in one of the common formats, or a Unix timestamp in seconds.
+func parseTime(s string) (time.Time, error) {
 	// Try common date formats
 	formats := []string{
 		time.RFC3339,
 		"2006-01-02T15:04:05Z0700",
 		"2006-01-02T15:04:05",
 		"2006-01-02",
+		time.RFC1123,
+		time.RFC1123Z,
+		time.RFC822,
 	}
 
 	for _, format := range formats {
 		if t, err := time.Parse(format, s); err == nil {
-			return t
+			return t, nil
+		}
+	}
+
+	// Fall back to Unix timestamps in seconds
+	if isDigits(s) {
+		if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return time.Unix(sec, 0).UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown date format %q", s)
+}
+
+// contains reports whether list holds s.
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isDigits reports whether s is a non-empty string of ASCII digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
 		}
 	}
-	return time.Time{}
+	return true
 }
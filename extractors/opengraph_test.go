@@ -0,0 +1,877 @@
+package extractor
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_startsNewMediaElement(t *testing.T) {
+	tests := []struct {
+		name          string
+		property      string
+		lastURL       string
+		lastSecureURL string
+		want          bool
+	}{
+		{
+			name:     "url after an element with url",
+			property: "og:image",
+			lastURL:  "http://example.com/a.jpg",
+			want:     true,
+		},
+		{
+			name:          "url after an element started by secure_url",
+			property:      "og:image",
+			lastSecureURL: "https://example.com/b.jpg",
+			want:          false,
+		},
+		{
+			name:     "secure_url after an element without secure_url",
+			property: "og:image:secure_url",
+			lastURL:  "http://example.com/a.jpg",
+			want:     false,
+		},
+		{
+			name:          "secure_url after an element with secure_url",
+			property:      "og:image:secure_url",
+			lastURL:       "http://example.com/a.jpg",
+			lastSecureURL: "https://example.com/a.jpg",
+			want:          true,
+		},
+		{
+			name:     "url alias after an element with url",
+			property: "og:image:url",
+			lastURL:  "http://example.com/a.jpg",
+			want:     true,
+		},
+		{
+			name:          "url alias after an element started by secure_url",
+			property:      "og:image:url",
+			lastSecureURL: "https://example.com/b.jpg",
+			want:          false,
+		},
+		{
+			name:          "other sub-property",
+			property:      "og:image:width",
+			lastURL:       "http://example.com/a.jpg",
+			lastSecureURL: "https://example.com/a.jpg",
+			want:          false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parts := strings.Split(test.property, ":")
+			if got := startsNewMediaElement(parts, test.lastURL, test.lastSecureURL); got != test.want {
+				t.Errorf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestParseOpenGraph_multipleImages(t *testing.T) {
+	content, err := os.ReadFile("../test/test-73-opengraph-multiple-images.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	got, errs := ParseOpenGraph("", string(content))
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	want := []OpenGraphImage{
+		{
+			URL:       "https://example.com/img/landscape.jpg",
+			SecureURL: "https://secure.example.com/img/landscape.jpg",
+			Type:      "image/jpeg",
+			Width:     1200,
+			Height:    630,
+			Alt:       "A landscape",
+		},
+		{
+			URL:       "https://example.com/img/portrait.png",
+			SecureURL: "https://secure.example.com/img/portrait.png",
+			Type:      "image/png",
+			Width:     630,
+			Height:    1200,
+			Alt:       "A portrait",
+		},
+	}
+	if og := got.(*OpenGraph); !reflect.DeepEqual(og.OpenGraphImage, want) {
+		t.Errorf("expected %+v, got %+v", want, og.OpenGraphImage)
+	}
+}
+
+func TestParseOpenGraph_mediaURLAlias(t *testing.T) {
+	content, err := os.ReadFile("../test/test-102-media-url-alias.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	got, errs := ParseOpenGraph("", string(content))
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	og := got.(*OpenGraph)
+	wantImages := []OpenGraphImage{
+		{URL: "https://example.com/img/first.jpg", Width: 800},
+		{URL: "https://example.com/img/second.jpg", Width: 600},
+	}
+	if !reflect.DeepEqual(og.OpenGraphImage, wantImages) {
+		t.Errorf("expected images %+v, got %+v", wantImages, og.OpenGraphImage)
+	}
+	wantVideos := []OpenGraphVideo{
+		{URL: "https://example.com/media/first.mp4"},
+		{URL: "https://example.com/media/second.mp4", Type: "video/mp4"},
+	}
+	if !reflect.DeepEqual(og.OpenGraphVideo, wantVideos) {
+		t.Errorf("expected videos %+v, got %+v", wantVideos, og.OpenGraphVideo)
+	}
+	wantAudios := []OpenGraphAudio{
+		{URL: "https://example.com/media/first.mp3"},
+		{URL: "https://example.com/media/second.mp3"},
+	}
+	if !reflect.DeepEqual(og.OpenGraphAudio, wantAudios) {
+		t.Errorf("expected audios %+v, got %+v", wantAudios, og.OpenGraphAudio)
+	}
+}
+
+func TestParseOpenGraphWithOptions_dedupeMedia(t *testing.T) {
+	content, err := os.ReadFile("../test/test-87-opengraph-duplicate-media.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		opts       Options
+		wantImages []OpenGraphImage
+		wantVideos []OpenGraphVideo
+	}{
+		{
+			name: "kept by default",
+			opts: Options{},
+			wantImages: []OpenGraphImage{
+				{URL: "https://example.com/img/cover.jpg"},
+				{URL: "https://example.com/img/cover.jpg", Width: 1200, Height: 630},
+				{URL: "https://example.com/img/other.jpg"},
+				{URL: "https://example.com/img/cover.jpg", Alt: "The cover"},
+			},
+			wantVideos: []OpenGraphVideo{
+				{URL: "https://example.com/video.mp4", Type: "video/mp4"},
+				{URL: "https://example.com/video.mp4"},
+			},
+		},
+		{
+			name: "collapsed",
+			opts: Options{OpenGraphDedupeMedia: true},
+			wantImages: []OpenGraphImage{
+				{URL: "https://example.com/img/cover.jpg", Width: 1200, Height: 630, Alt: "The cover"},
+				{URL: "https://example.com/img/other.jpg"},
+			},
+			wantVideos: []OpenGraphVideo{
+				{URL: "https://example.com/video.mp4", Type: "video/mp4"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, errs := ParseOpenGraphWithOptions("", string(content), test.opts)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			og := got.(*OpenGraph)
+			if !reflect.DeepEqual(og.OpenGraphImage, test.wantImages) {
+				t.Errorf("expected images %+v, got %+v", test.wantImages, og.OpenGraphImage)
+			}
+			if !reflect.DeepEqual(og.OpenGraphVideo, test.wantVideos) {
+				t.Errorf("expected videos %+v, got %+v", test.wantVideos, og.OpenGraphVideo)
+			}
+
+			gotXCards, _ := ParseXCardsWithOptions("", string(content), test.opts)
+			if xc := gotXCards.(*XCards); !reflect.DeepEqual(xc.OpenGraphImage, test.wantImages) {
+				t.Errorf("expected X Cards images %+v, got %+v", test.wantImages, xc.OpenGraphImage)
+			}
+		})
+	}
+}
+
+func TestParseXCardsWithOptions_subPropertyFirst(t *testing.T) {
+	content := `<meta name="twitter:image:alt" content="An image">
+<meta name="twitter:image" content="https://example.com/a.jpg">`
+
+	got, _ := ParseXCardsWithOptions("", content, Options{XCardsSkipOpenGraph: true})
+
+	want := []XCardsImage{{URL: "https://example.com/a.jpg", Alt: "An image"}}
+	if xc, ok := got.(*XCards); !ok || !reflect.DeepEqual(xc.XCardsImage, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestParseOpenGraphWithOptions_strict(t *testing.T) {
+	content, err := os.ReadFile("../test/test-60-opengraph-invalid-determiner.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     Options
+		wantErrs int
+	}{
+		{
+			name:     "not strict",
+			opts:     Options{},
+			wantErrs: 0,
+		},
+		{
+			name:     "strict",
+			opts:     Options{OpenGraphStrict: true},
+			wantErrs: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, errs := ParseOpenGraphWithOptions("", string(content), test.opts)
+			if len(errs) != test.wantErrs {
+				t.Fatalf("expected %d errors, got %v", test.wantErrs, errs)
+			}
+			if test.wantErrs > 0 && !errors.Is(errs[0], ErrInvalidOpenGraphValue) {
+				t.Errorf("expected ErrInvalidOpenGraphValue, got %v", errs[0])
+			}
+			if og, ok := got.(*OpenGraph); !ok || og.Determiner != "some" {
+				t.Errorf("expected the determiner to be kept, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestParseOpenGraphWithOptions_strictMalformed(t *testing.T) {
+	content, err := os.ReadFile("../test/test-91-opengraph-malformed.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	_, errs := ParseOpenGraphWithOptions("", string(content), Options{})
+	if len(errs) != 0 {
+		t.Errorf("expected no errors when not strict, got %v", errs)
+	}
+
+	got, errs := ParseOpenGraphWithOptions("", string(content), Options{OpenGraphStrict: true})
+	want := []struct {
+		err     error
+		message string
+	}{
+		{ErrUnknownOpenGraphProperty, `og:colour "blue"`},
+		{ErrInvalidOpenGraphValue, `og:image:width "wide" is not an integer`},
+		{ErrInvalidOpenGraphValue, `article:published_time "last Tuesday" is not a date or time`},
+		{ErrMissingOpenGraphProperty, "og:url"},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d errors, got %v", len(want), errs)
+	}
+	for i, w := range want {
+		if !errors.Is(errs[i], w.err) || !strings.Contains(errs[i].Error(), w.message) {
+			t.Errorf("expected error %d to wrap %v and name %s, got %v", i, w.err, w.message, errs[i])
+		}
+	}
+
+	og := got.(*OpenGraph)
+	if og.OpenGraphImage[0].Width != 0 || og.OpenGraphImage[0].Height != 1200 || !og.Article.PublishedTime.IsZero() {
+		t.Errorf("expected the values to be parsed as when not strict, got %+v and %+v", og.OpenGraphImage, og.Article)
+	}
+	if !reflect.DeepEqual(og.Extra["og:colour"], []string{"blue"}) {
+		t.Errorf("expected the unknown property to be kept, got %v", og.Extra)
+	}
+}
+
+func TestParseOpenGraph_unknownMediaSubProperty(t *testing.T) {
+	content := `<html><head>
+<meta property="og:type" content="website">
+<meta property="og:title" content="Gallery">
+<meta property="og:url" content="https://example.com/gallery">
+<meta property="og:image" content="https://example.com/img/a.jpg">
+<meta property="og:image:foo" content="bar">
+<meta property="og:image:width" content="800">
+<meta property="og:video:rating" content="pg">
+<meta property="og:audio:artist" content="Jane">
+</head></html>`
+
+	got, errs := ParseOpenGraph("", content)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	og := got.(*OpenGraph)
+	wantImages := []OpenGraphImage{{URL: "https://example.com/img/a.jpg", Width: 800}}
+	if !reflect.DeepEqual(og.OpenGraphImage, wantImages) {
+		t.Errorf("expected images %+v, got %+v", wantImages, og.OpenGraphImage)
+	}
+	if og.OpenGraphVideo != nil || og.OpenGraphAudio != nil {
+		t.Errorf("expected no video or audio, got %+v and %+v", og.OpenGraphVideo, og.OpenGraphAudio)
+	}
+	wantExtra := map[string][]string{
+		"og:image:foo":    {"bar"},
+		"og:video:rating": {"pg"},
+		"og:audio:artist": {"Jane"},
+	}
+	if !reflect.DeepEqual(og.Extra, wantExtra) {
+		t.Errorf("expected extra %v, got %v", wantExtra, og.Extra)
+	}
+
+	_, errs = ParseOpenGraphWithOptions("", content, Options{OpenGraphStrict: true})
+	if len(errs) != len(wantExtra) {
+		t.Fatalf("expected %d errors, got %v", len(wantExtra), errs)
+	}
+	for _, err := range errs {
+		if !errors.Is(err, ErrUnknownOpenGraphProperty) {
+			t.Errorf("expected ErrUnknownOpenGraphProperty, got %v", err)
+		}
+	}
+}
+
+func TestParseOpenGraph_locale(t *testing.T) {
+	content, err := os.ReadFile("../test/test-96-opengraph-locale.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	got, errs := ParseOpenGraph("", string(content))
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	og := got.(*OpenGraph)
+	if og.Locale != "en_US" {
+		t.Errorf("expected og:locale en_US, got %q", og.Locale)
+	}
+	wantAlternate := []string{"en", "pt_BR", "es_419", "english"}
+	if !reflect.DeepEqual(og.LocaleAlternate, wantAlternate) {
+		t.Errorf("expected og:locale:alternate %v, got %v", wantAlternate, og.LocaleAlternate)
+	}
+	wantExtra := map[string][]string{
+		"og:locale":           {"en-US"},
+		"og:locale:alternate": {"pt-br"},
+	}
+	if !reflect.DeepEqual(og.Extra, wantExtra) {
+		t.Errorf("expected the raw values %v, got %v", wantExtra, og.Extra)
+	}
+
+	_, errs = ParseOpenGraphWithOptions("", string(content), Options{OpenGraphStrict: true})
+	if len(errs) != 1 || !errors.Is(errs[0], ErrInvalidOpenGraphValue) || !strings.Contains(errs[0].Error(), `og:locale:alternate "english" is not a locale`) {
+		t.Errorf("expected an error for the invalid locale, got %v", errs)
+	}
+}
+
+func Test_normalizeOpenGraphLocale(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   string
+		valid  bool
+	}{
+		{"en_US", "en_US", true},
+		{"en-US", "en_US", true},
+		{"EN-us", "en_US", true},
+		{"en", "en", true},
+		{"fil_PH", "fil_PH", true},
+		{"es-419", "es_419", true},
+		{"", "", false},
+		{"e", "e", false},
+		{"en-USA", "en-USA", false},
+		{"en_", "en_", false},
+		{"zh-Hans-CN", "zh-Hans-CN", false},
+	}
+	for _, test := range tests {
+		t.Run(test.locale, func(t *testing.T) {
+			got, valid := normalizeOpenGraphLocale(test.locale)
+			if got != test.want || valid != test.valid {
+				t.Errorf("expected %q, %v, got %q, %v", test.want, test.valid, got, valid)
+			}
+		})
+	}
+}
+
+func TestOpenGraph_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		fixture     string
+		wantMissing []string
+	}{
+		{
+			name:        "complete",
+			fixture:     "test-92-opengraph-required.html",
+			wantMissing: nil,
+		},
+		{
+			name:        "missing og:image",
+			fixture:     "test-01-opengraph-minimal.html",
+			wantMissing: []string{"og:image"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			content, err := os.ReadFile("../test/" + test.fixture)
+			if err != nil {
+				t.Fatalf("reading fixture: %v", err)
+			}
+			got, _ := ParseOpenGraph("", string(content))
+
+			errs := got.(*OpenGraph).Validate()
+			if len(errs) != len(test.wantMissing) {
+				t.Fatalf("expected %d errors, got %v", len(test.wantMissing), errs)
+			}
+			for i, property := range test.wantMissing {
+				if !errors.Is(errs[i], ErrMissingOpenGraphProperty) || !strings.HasSuffix(errs[i].Error(), property) {
+					t.Errorf("expected ErrMissingOpenGraphProperty for %s, got %v", property, errs[i])
+				}
+			}
+		})
+	}
+
+	if errs := NewOpenGraph().Validate(); len(errs) != 4 {
+		t.Errorf("expected 4 errors for an empty OpenGraph, got %v", errs)
+	}
+}
+
+func Test_validateOpenGraph(t *testing.T) {
+	for _, determiner := range []string{"a", "an", "the", "", "auto", " The "} {
+		if errs := validateOpenGraph(&OpenGraph{Determiner: determiner}); len(errs) > 0 {
+			t.Errorf("expected %q to be valid, got %v", determiner, errs)
+		}
+	}
+	if errs := validateOpenGraph(&OpenGraph{Determiner: "some"}); len(errs) != 1 {
+		t.Errorf("expected %q to be invalid, got %v", "some", errs)
+	}
+}
+
+func TestParseOpenGraph_relativeURLs(t *testing.T) {
+	content, err := os.ReadFile("../test/test-70-opengraph-relative-urls.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		URL    string
+		url    string
+		images []OpenGraphImage
+		videos []OpenGraphVideo
+		audios []OpenGraphAudio
+	}{
+		{
+			name: "with page URL",
+			URL:  "https://example.com/blog/index.html",
+			url:  "https://example.com/articles/relative.html",
+			images: []OpenGraphImage{
+				{URL: "https://example.com/img/cover.jpg", SecureURL: "https://example.com/blog/img/cover-secure.jpg"},
+				{URL: "https://cdn.example.com/img/second.jpg"},
+			},
+			videos: []OpenGraphVideo{{URL: "https://example.com/media/clip.mp4"}},
+			audios: []OpenGraphAudio{{URL: "https://cdn.example.com/media/track.mp3"}},
+		},
+		{
+			name: "without page URL",
+			URL:  "",
+			url:  "/articles/relative.html",
+			images: []OpenGraphImage{
+				{URL: "/img/cover.jpg", SecureURL: "img/cover-secure.jpg"},
+				{URL: "https://cdn.example.com/img/second.jpg"},
+			},
+			videos: []OpenGraphVideo{{URL: "../media/clip.mp4"}},
+			audios: []OpenGraphAudio{{URL: "//cdn.example.com/media/track.mp3"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, errs := ParseOpenGraph(test.URL, string(content))
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			og := got.(*OpenGraph)
+			if og.URL != test.url {
+				t.Errorf("expected og:url %q, got %q", test.url, og.URL)
+			}
+			if !reflect.DeepEqual(og.OpenGraphImage, test.images) {
+				t.Errorf("expected images %+v, got %+v", test.images, og.OpenGraphImage)
+			}
+			if !reflect.DeepEqual(og.OpenGraphVideo, test.videos) {
+				t.Errorf("expected videos %+v, got %+v", test.videos, og.OpenGraphVideo)
+			}
+			if !reflect.DeepEqual(og.OpenGraphAudio, test.audios) {
+				t.Errorf("expected audios %+v, got %+v", test.audios, og.OpenGraphAudio)
+			}
+		})
+	}
+}
+
+func TestParseOpenGraph_baseHref(t *testing.T) {
+	content, err := os.ReadFile("../test/test-97-base-href.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	got, errs := ParseOpenGraph("https://example.com/catalog/anvil", string(content))
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	og := got.(*OpenGraph)
+	if want := "https://cdn.example.com/products/anvil"; og.URL != want {
+		t.Errorf("expected og:url %q, got %q", want, og.URL)
+	}
+	if want := []OpenGraphImage{{URL: "https://cdn.example.com/assets/images/anvil-og.png"}}; !reflect.DeepEqual(og.OpenGraphImage, want) {
+		t.Errorf("expected images %+v, got %+v", want, og.OpenGraphImage)
+	}
+}
+
+func TestParseOpenGraphWithOptions_isbn(t *testing.T) {
+	tests := []struct {
+		fixture string
+		isbn    string
+		wantErr string
+	}{
+		{
+			fixture: "test-98-opengraph-book-isbn.html",
+			isbn:    "9780306406157",
+		},
+		{
+			fixture: "test-99-opengraph-book-isbn-invalid.html",
+			isbn:    "9780306406158",
+			wantErr: `book:isbn "978 0 306 40615 8" is not a valid ISBN-10 or ISBN-13`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.fixture, func(t *testing.T) {
+			content, err := os.ReadFile("../test/" + test.fixture)
+			if err != nil {
+				t.Fatalf("reading fixture: %v", err)
+			}
+
+			got, errs := ParseOpenGraph("", string(content))
+			if len(errs) > 0 {
+				t.Fatalf("expected no errors when not strict, got %v", errs)
+			}
+			if isbn := got.(*OpenGraph).Book.ISBN; isbn != test.isbn {
+				t.Errorf("expected ISBN %q, got %q", test.isbn, isbn)
+			}
+
+			_, errs = ParseOpenGraphWithOptions("", string(content), Options{OpenGraphStrict: true})
+			if test.wantErr == "" {
+				if len(errs) > 0 {
+					t.Errorf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !errors.Is(errs[0], ErrInvalidOpenGraphValue) || !strings.Contains(errs[0].Error(), test.wantErr) {
+				t.Errorf("expected an error naming %s, got %v", test.wantErr, errs)
+			}
+		})
+	}
+}
+
+func Test_isValidISBN(t *testing.T) {
+	tests := []struct {
+		isbn string
+		want bool
+	}{
+		{"9780306406157", true},
+		{"9780306406158", false},
+		{"0306406152", true},
+		{"0306406153", false},
+		{"080442957X", true},
+		{"X804429570", false},
+		{"978030640615", false},
+		{"97803064061AB", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		t.Run(test.isbn, func(t *testing.T) {
+			if got := isValidISBN(test.isbn); got != test.want {
+				t.Errorf("isValidISBN(%q) = %v, want %v", test.isbn, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseOpenGraph_extra(t *testing.T) {
+	content := `<html><head>
+<meta property="og:title" content="Pizzeria">
+<meta property="og:restaurant:menu" content="https://example.com/menu">
+<meta property="fb:app_id" content="174829003346">
+<meta property="article:author" content="https://example.com/jane">
+<meta property="article:publisher" content="https://example.com/">
+<meta property="restaurant:price_rating" content="2">
+<meta property="restaurant:price_rating" content="3">
+</head></html>`
+
+	got, errs := ParseOpenGraph("", content)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	want := map[string][]string{
+		"og:restaurant:menu":      {"https://example.com/menu"},
+		"fb:app_id":               {"174829003346"},
+		"article:publisher":       {"https://example.com/"},
+		"restaurant:price_rating": {"2", "3"},
+	}
+	og := got.(*OpenGraph)
+	if !reflect.DeepEqual(og.Extra, want) {
+		t.Errorf("expected %v, got %v", want, og.Extra)
+	}
+	if og.Title != "Pizzeria" || og.Article == nil || len(og.Article.Author) != 1 {
+		t.Errorf("expected the known properties to be set, got %+v", og)
+	}
+}
+
+func TestParseOpenGraph_malformedUpdatedTime(t *testing.T) {
+	content := `<meta property="og:updated_time" content="yesterday">
+<meta property="og:ttl" content="3600">`
+
+	got, errs := ParseOpenGraph("", content)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	og := got.(*OpenGraph)
+	if !og.UpdatedTime.IsZero() {
+		t.Errorf("expected a zero updated time, got %v", og.UpdatedTime)
+	}
+	if og.TTL != 3600 {
+		t.Errorf("expected a TTL of 3600, got %d", og.TTL)
+	}
+}
+
+func TestOpenGraph_MarshalJSON(t *testing.T) {
+	encoded, err := json.Marshal(&OpenGraph{Title: "Fresh", TTL: 3600})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `{"og:type":"","og:title":"Fresh","og:url":"","og:ttl":3600}`; string(encoded) != want {
+		t.Errorf("expected %s, got %s", want, encoded)
+	}
+
+	updated := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	encoded, err = json.Marshal(OpenGraph{Title: "Fresh", UpdatedTime: updated})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `{"og:type":"","og:title":"Fresh","og:url":"","og:updated_time":"2025-01-02T03:04:05Z"}`; string(encoded) != want {
+		t.Errorf("expected %s, got %s", want, encoded)
+	}
+}
+
+func TestParseOpenGraph_articleAuthors(t *testing.T) {
+	content, err := os.ReadFile("../test/test-75-opengraph-article-authors.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	got, errs := ParseOpenGraph("", string(content))
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	og := got.(*OpenGraph)
+	wantAuthors := []string{
+		"https://www.example.com/profileAuthorA.html",
+		"https://www.example.com/profileAuthorB.html",
+	}
+	if og.Article == nil || !reflect.DeepEqual(og.Article.Author, wantAuthors) {
+		t.Fatalf("expected authors %v, got %+v", wantAuthors, og.Article)
+	}
+	wantProfiles := []ArticleAuthor{
+		{
+			URL:     "https://www.example.com/profileAuthorA.html",
+			Profile: Profile{FirstName: "John", LastName: "Doe"},
+		},
+		{
+			URL:     "https://www.example.com/profileAuthorB.html",
+			Profile: Profile{FirstName: "Jane", LastName: "Roe"},
+		},
+	}
+	if !reflect.DeepEqual(og.Article.AuthorProfile, wantProfiles) {
+		t.Errorf("expected author profiles %+v, got %+v", wantProfiles, og.Article.AuthorProfile)
+	}
+}
+
+func TestParseOpenGraph_articleAuthorProfileAfterOtherTags(t *testing.T) {
+	content := `<html><head>
+<meta property="og:type" content="article">
+<meta property="article:author" content="https://www.example.com/profileAuthorA.html">
+<meta property="profile:first_name" content="John">
+<meta property="article:section" content="Front page">
+<meta property="og:title" content="Title">
+<meta property="profile:username" content="site-owner">
+</head></html>`
+
+	got, errs := ParseOpenGraph("", content)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	og := got.(*OpenGraph)
+	wantProfiles := []ArticleAuthor{
+		{URL: "https://www.example.com/profileAuthorA.html", Profile: Profile{FirstName: "John"}},
+	}
+	if !reflect.DeepEqual(og.Article.AuthorProfile, wantProfiles) {
+		t.Errorf("expected author profiles %+v, got %+v", wantProfiles, og.Article.AuthorProfile)
+	}
+	if want := (&Profile{Username: "site-owner"}); !reflect.DeepEqual(og.Profile, want) {
+		t.Errorf("expected the profile of the page %+v, got %+v", want, og.Profile)
+	}
+}
+
+func TestParseOpenGraph_musicReleaseDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    time.Time
+	}{
+		{
+			name:    "valid",
+			content: "2011-04-19",
+			want:    time.Date(2011, 4, 19, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "invalid",
+			content: "spring 2011",
+			want:    time.Time{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, _ := ParseOpenGraph("", `<meta property="music:release_date" content="`+test.content+`">`)
+			if og := got.(*OpenGraph); og.Music == nil || !og.Music.ReleaseDate.Equal(test.want) {
+				t.Errorf("expected %v, got %+v", test.want, og.Music)
+			}
+		})
+	}
+}
+
+func Test_parseTimeSafely(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  time.Time
+	}{
+		{
+			name:  "RFC3339",
+			input: "2024-10-01T12:30:00+02:00",
+			want:  time.Date(2024, 10, 1, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "date",
+			input: "2024-10-01",
+			want:  time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "RFC1123",
+			input: "Tue, 01 Oct 2024 12:30:00 UTC",
+			want:  time.Date(2024, 10, 1, 12, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "RFC1123Z",
+			input: "Tue, 01 Oct 2024 12:30:00 +0200",
+			want:  time.Date(2024, 10, 1, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "RFC822",
+			input: "01 Oct 24 12:30 UTC",
+			want:  time.Date(2024, 10, 1, 12, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "Unix timestamp",
+			input: "1727785800",
+			want:  time.Date(2024, 10, 1, 12, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "negative number",
+			input: "-1727785800",
+			want:  time.Time{},
+		},
+		{
+			name:  "invalid",
+			input: "yesterday",
+			want:  time.Time{},
+		},
+		{
+			name:  "empty",
+			input: "",
+			want:  time.Time{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := parseTimeSafely(test.input); !got.Equal(test.want) {
+				t.Errorf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func Test_parseInt(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{input: "1200.0", want: 1200},
+		{input: "0.0", want: 0},
+		{input: "1,200.0", want: 1200},
+		{input: "+12", want: 12},
+		{input: "abc", wantErr: true},
+		{input: "12px", wantErr: true},
+		{input: "1200.5", wantErr: true},
+		{input: ".5", wantErr: true},
+		{input: "630.9", wantErr: true},
+		{input: "1.2e3", wantErr: true},
+		{input: "1.", wantErr: true},
+		{input: "NaN", wantErr: true},
+		{input: "Inf", wantErr: true},
+		{input: ".", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			got, err := parseInt(test.input)
+			if (err != nil) != test.wantErr || got != test.want {
+				t.Errorf("expected %d, error %v, got %d, %v", test.want, test.wantErr, got, err)
+			}
+		})
+	}
+}
+
+func Test_parseIntSafely(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{input: "1280", want: 1280},
+		{input: "1,280", want: 1280},
+		{input: "1 280", want: 1280},
+		{input: "1\u00a0280", want: 1280},
+		{input: " 42 ", want: 42},
+		{input: "\t42\n", want: 42},
+		{input: "-5", want: -5},
+		{input: "-1,000", want: -1000},
+		{input: "1200.0", want: 1200},
+		{input: "0.0", want: 0},
+		{input: ".5", want: 0},
+		{input: "630.9", want: 0},
+		{input: "1.2e3", want: 0},
+		{input: "1,200.0", want: 1200},
+		{input: "abc", want: 0},
+		{input: "", want: 0},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			if got := parseIntSafely(test.input); got != test.want {
+				t.Errorf("expected %d, got %d", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package extractor
+
+// Options represents the settings that tune the behavior of the parsers.
+type Options struct {
+	// JSONLDPassthrough returns the JSON-LD nodes as decoded, disabling every normalization step regardless of its
+	// own option, like JSONLDPropagateContext, and the flattening of @graph arrays. The block limits still apply.
+	JSONLDPassthrough bool
+
+	// JSONLDKeepGraphs keeps the nodes holding only a @graph array as they are, instead of flattening them into the
+	// objects of the array.
+	JSONLDKeepGraphs bool
+
+	// JSONLDPropagateContext propagates the @context of the first node of a JSON-LD array to its nodes without one.
+	JSONLDPropagateContext bool
+
+	// JSONLDExpand expands the keys and the types of the JSON-LD nodes to full IRIs against their @context, like
+	// "author" to "https://schema.org/author", removing the @context.
+	JSONLDExpand bool
+
+	// JSONLDNormalizeType collapses the @type arrays of a single type into a string, like ["Product"] into "Product",
+	// and sorts the other @type arrays, in every node, nested ones included.
+	JSONLDNormalizeType bool
+
+	// JSONLDMaxBlockBytes is the maximum size of a JSON-LD script block, larger blocks are skipped with an error
+	// wrapping ErrJSONLDBlockTooLarge. 0 means unlimited.
+	JSONLDMaxBlockBytes int
+
+	// JSONLDMaxBlocks is the maximum number of JSON-LD script blocks parsed, the following ones are ignored.
+	// Empty and skipped blocks are not counted. 0 means unlimited.
+	JSONLDMaxBlocks int
+
+	// OpenGraphStrict validates the Open Graph values against the protocol, recording an error wrapping
+	// ErrInvalidOpenGraphValue for each invalid or unparseable one, ErrUnknownOpenGraphProperty for each unknown
+	// property of its namespaces and ErrMissingOpenGraphProperty for each missing og:type, og:title or og:url. The
+	// values are kept. The book:isbn values of X Cards are validated as well.
+	OpenGraphStrict bool
+
+	// OpenGraphDedupeMedia collapses the Open Graph images, videos and audios with the same URL into their first
+	// occurrence, filling its empty structured properties from the later ones. This applies to X Cards filled from
+	// Open Graph as well.
+	OpenGraphDedupeMedia bool
+
+	// XCardsSkipOpenGraph returns only the fields of X Cards declared by twitter: meta tags, without filling the
+	// missing ones from OpenGraph.
+	XCardsSkipOpenGraph bool
+
+	// MicrodataPreferContent reads the value of a microdata property from the content attribute of any element, not
+	// only of meta elements, before falling back to its text.
+	MicrodataPreferContent bool
+}
+
+// jsonLDNormalize reports whether a JSON-LD normalization step enabled by its own option is applied, which it is
+// unless JSONLDPassthrough is set.
+func (o Options) jsonLDNormalize(step bool) bool {
+	return step && !o.JSONLDPassthrough
+}
@@ -1,26 +1,129 @@
 package extractor
 
 import (
-	"fmt"
+	"bytes"
+	"encoding/json"
 	"golang.org/x/net/html"
-	"net/url"
+	"io"
+	"sort"
 	"strings"
 )
 
+// MicrodataItem represents a microdata item. A property given once holds its value, a string or a *MicrodataItem
+// for a nested item; a property given several times holds an []any of those in document order, which may mix strings
+// and nested items. Values returns the values of a property in either case.
 type MicrodataItem struct {
+	// Type is the first, primary type of the item, Types lists all the space-separated types of its itemtype
 	Type       string         `json:"type,omitempty"`
+	Types      []string       `json:"types,omitempty"`
 	ID         *string        `json:"id,omitempty"`
 	Properties map[string]any `json:"properties,omitempty"`
+	// PropertyOrder lists the names of Properties in the order they were first seen in the document
+	PropertyOrder []string `json:"-"`
+}
+
+// MarshalJSON encodes the item like its fields would be, with the properties in the order of PropertyNames.
+func (item MicrodataItem) MarshalJSON() ([]byte, error) {
+	encoded := struct {
+		Type       string             `json:"type,omitempty"`
+		Types      []string           `json:"types,omitempty"`
+		ID         *string            `json:"id,omitempty"`
+		Properties *orderedProperties `json:"properties,omitempty"`
+	}{
+		Type:  item.Type,
+		Types: item.Types,
+		ID:    item.ID,
+	}
+	if len(item.Properties) > 0 {
+		encoded.Properties = &orderedProperties{names: item.PropertyNames(), values: item.Properties}
+	}
+
+	return json.Marshal(encoded)
+}
+
+// orderedProperties encodes the properties of an item as a JSON object, with the keys in the order of names.
+type orderedProperties struct {
+	names  []string
+	values map[string]any
+}
+
+func (op *orderedProperties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+	for i, name := range op.names {
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(op.values[name])
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// PropertyNames returns the names of the properties of the item in the order they were first seen in the document,
+// followed by those missing from PropertyOrder, like on an item built by hand, sorted by name.
+func (item MicrodataItem) PropertyNames() []string {
+	names := make([]string, 0, len(item.Properties))
+	seen := make(map[string]bool, len(item.Properties))
+	for _, name := range item.PropertyOrder {
+		if _, ok := item.Properties[name]; ok && !seen[name] {
+			names = append(names, name)
+			seen[name] = true
+		}
+	}
+
+	var rest []string
+	for name := range item.Properties {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+
+	return append(names, rest...)
+}
+
+// Values returns the values of the named property, each a string or a *MicrodataItem, whether it was given once or
+// several times. It returns nil if the item has no such property.
+func (item MicrodataItem) Values(name string) []any {
+	switch value := item.Properties[name].(type) {
+	case nil:
+		return nil
+	case []any:
+		return value
+	default:
+		return []any{value}
+	}
 }
 
 func W3CMicrodata(URL string, htmlContent string) ([]MicrodataItem, []error) {
-	items, errors := parseW3CMicrodata(URL, htmlContent)
+	return W3CMicrodataWithOptions(URL, htmlContent, Options{})
+}
+
+// W3CMicrodataWithOptions extracts the microdata items of the HTML content like W3CMicrodata, using the given parser
+// options.
+func W3CMicrodataWithOptions(URL string, htmlContent string, opts Options) ([]MicrodataItem, []error) {
+	items, errors := parseW3CMicrodata(URL, htmlContent, opts)
 
 	var results []MicrodataItem
 	for _, item := range items {
 		result := MicrodataItem{
-			Type:       item.Type,
-			Properties: item.Properties,
+			Type:          item.Type,
+			Types:         item.Types,
+			Properties:    item.Properties,
+			PropertyOrder: item.PropertyOrder,
 		}
 		if item.ID != nil {
 			result.ID = item.ID
@@ -33,86 +136,229 @@ func W3CMicrodata(URL string, htmlContent string) ([]MicrodataItem, []error) {
 }
 
 // parseW3CMicrodata parses an HTML input string to extract W3C microdata items and returns them along with any errors.
-func parseW3CMicrodata(URL string, input string) ([]*MicrodataItem, []error) {
+func parseW3CMicrodata(URL string, input string, opts Options) ([]*MicrodataItem, []error) {
+	return parseW3CMicrodataReader(URL, strings.NewReader(input), opts)
+}
+
+// parseW3CMicrodataReader parses the microdata items of the HTML read from r. If reading fails, the error is recorded
+// and the items of the content read until then are returned.
+func parseW3CMicrodataReader(URL string, r io.Reader, opts Options) ([]*MicrodataItem, []error) {
 	var errors []error
 
-	// strings.NewReader() always provides a valid reader for html.Parse()
-	doc, _ := html.Parse(strings.NewReader(input))
+	var consumed bytes.Buffer
+	doc, err := html.Parse(io.TeeReader(r, &consumed))
+	if err != nil {
+		errors = append(errors, err)
+		// html.Parse drops the whole tree on error, the content read so far still gives a partial one
+		doc, _ = html.Parse(&consumed)
+	}
+
+	p := newMicrodataParser(URL, doc, opts)
 
 	var items []*MicrodataItem
-	var parseNode func(*html.Node)
-	parseNode = func(n *html.Node) {
+	// inItem is set below an itemscope element, where an item with an itemprop is a property of an enclosing item, but
+	// one without is a top-level item of its own
+	var parseNode func(n *html.Node, inItem bool)
+	parseNode = func(n *html.Node, inItem bool) {
 		if n.Type == html.ElementNode && getAttr(n, "itemscope") {
-			item := &MicrodataItem{
-				Properties: make(map[string]any),
+			isProperty := getAttr(n, "itemprop")
+			// an item referenced as a property by itemref is not a top-level item
+			if !(isProperty && (inItem || p.referenced[getAttrVal(n, "id")])) {
+				items = append(items, p.parseItem(n))
+			}
+			inItem = true
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			parseNode(c, inItem)
+		}
+	}
+	parseNode(doc, false)
+
+	return items, errors
+}
+
+// microdataURLAttrs holds the attribute giving the URL value of a microdata property by element, like the src of an
+// <img> or the href of an <a>, read before falling back to the text content.
+var microdataURLAttrs = map[string]string{
+	"a":      "href",
+	"img":    "src",
+	"audio":  "src",
+	"video":  "src",
+	"source": "src",
+	"embed":  "src",
+	"track":  "src",
+	"iframe": "src",
+	"object": "data",
+	"link":   "href",
+	"area":   "href",
+}
+
+// microdataParser holds the state of parsing the microdata items of a document.
+type microdataParser struct {
+	// URL is the base URL of the document, the href of its first <base> element if any, or else the page URL.
+	URL  string
+	opts Options
+	// ids holds the elements by their id, for resolving itemref.
+	ids map[string]*html.Node
+	// referenced holds the ids listed by the itemref attributes of the document.
+	referenced map[string]bool
+	// resolving holds the referenced elements being parsed, to break itemref cycles.
+	resolving map[*html.Node]bool
+}
+
+// newMicrodataParser returns a parser of the microdata items of doc, indexing its elements by id and finding its base
+// URL.
+func newMicrodataParser(URL string, doc *html.Node, opts Options) *microdataParser {
+	p := &microdataParser{
+		URL:        URL,
+		opts:       opts,
+		ids:        make(map[string]*html.Node),
+		referenced: make(map[string]bool),
+		resolving:  make(map[*html.Node]bool),
+	}
+
+	hasBase := false
+	var index func(*html.Node)
+	index = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			// only the first <base> element with an href is used, as in browsers
+			if n.Data == "base" && !hasBase && getAttr(n, "href") {
+				p.URL = baseHrefURL(URL, getAttrVal(n, "href"))
+				hasBase = true
 			}
-			itemType := getAttrVal(n, "itemtype")
-			if itemType != "" {
-				item.Type = itemType
+			// the first element with a given id wins, as with getElementById
+			if id := getAttrVal(n, "id"); id != "" && p.ids[id] == nil {
+				p.ids[id] = n
 			}
-			itemID := getAttrVal(n, "itemid")
-			if itemID != "" {
-				item.ID = &itemID
+			for _, id := range strings.Fields(getAttrVal(n, "itemref")) {
+				p.referenced[id] = true
 			}
-			parseProperties(n, item, URL)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			index(c)
+		}
+	}
+	if doc != nil {
+		index(doc)
+	}
 
-			items = append(items, item)
-		} else {
-			for c := n.FirstChild; c != nil; c = c.NextSibling {
-				parseNode(c)
-			}
+	return p
+}
+
+// parseItem returns the item of the itemscope element n, with the properties of its descendants and of the elements
+// referenced by its itemref attribute.
+func (p *microdataParser) parseItem(n *html.Node) *MicrodataItem {
+	item := &MicrodataItem{
+		Properties: make(map[string]any),
+	}
+	if types := strings.Fields(getAttrVal(n, "itemtype")); len(types) > 0 {
+		item.Type = types[0]
+		item.Types = types
+	}
+	itemID := getAttrVal(n, "itemid")
+	if itemID != "" {
+		item.ID = &itemID
+	}
+	p.parseProperties(n, item)
+
+	for _, id := range strings.Fields(getAttrVal(n, "itemref")) {
+		ref := p.ids[id]
+		if ref == nil || p.resolving[ref] {
+			continue
 		}
+		p.resolving[ref] = true
+		p.parseProperty(ref, item)
+		delete(p.resolving, ref)
 	}
-	parseNode(doc)
 
-	return items, errors
+	return item
 }
 
-func parseProperties(n *html.Node, item *MicrodataItem, URL string) {
+// parseProperties adds the properties of the descendants of n to item.
+func (p *microdataParser) parseProperties(n *html.Node, item *MicrodataItem) {
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
 		if c.Type == html.ElementNode {
-			if prop := getAttrVal(c, "itemprop"); prop != "" {
-				if getAttr(c, "itemscope") {
-					subItem := &MicrodataItem{
-						Properties: make(map[string]any),
-					}
-					subItemType := getAttrVal(c, "itemtype")
-					if subItemType != "" {
-						subItem.Type = subItemType
-					}
-					subItemID := getAttrVal(c, "itemid")
-					if subItemID != "" {
-						subItem.ID = &subItemID
-					}
-					parseProperties(c, subItem, URL)
-					item.Properties[prop] = appendValue(item.Properties[prop], subItem)
-				} else {
-					value := getTextContent(c)
-					attrContent := getAttrVal(c, "content")
-					if c.Data == "meta" && attrContent != "" {
-						value = attrContent
-					} else if datetime := getAttrVal(c, "datetime"); datetime != "" {
-						value = datetime
-					} else if prop == "url" || strings.HasSuffix(prop, "Url") {
-						href := getAttrVal(c, "href")
-						if strings.HasPrefix(href, "//") || strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://") {
-							value = href
-						} else {
-							baseURL := ""
-							parsedURL, err := url.Parse(URL)
-							if err == nil {
-								baseURL = fmt.Sprintf("%s://%s", parsedURL.Scheme, parsedURL.Host)
-							}
-							value = baseURL + href
-						}
-					}
-					item.Properties[prop] = appendValue(item.Properties[prop], value)
-				}
-			} else {
-				parseProperties(c, item, URL)
+			p.parseProperty(c, item)
+		}
+	}
+}
+
+// parseProperty adds the property of the element n to item, or the properties of its descendants if it has no
+// itemprop attribute. An itemprop listing several space-separated names adds the same value under each of them.
+func (p *microdataParser) parseProperty(n *html.Node, item *MicrodataItem) {
+	props := strings.Fields(getAttrVal(n, "itemprop"))
+	if len(props) == 0 {
+		// the descendants of an item that is not a property belong to that item, not to the enclosing one
+		if !getAttr(n, "itemscope") {
+			p.parseProperties(n, item)
+		}
+		return
+	}
+	if getAttr(n, "itemscope") {
+		nested := p.parseItem(n)
+		if !nested.isEmpty() {
+			for _, prop := range props {
+				item.addProperty(prop, nested)
 			}
+			return
+		}
+		// an item without type, id or properties falls back to the value of the element, like its text
+	}
+
+	value := p.propertyValue(n)
+	for _, prop := range props {
+		item.addProperty(prop, value)
+	}
+}
+
+// isEmpty reports whether the item has no type, id or properties.
+func (item *MicrodataItem) isEmpty() bool {
+	return item.Type == "" && item.ID == nil && len(item.Properties) == 0
+}
+
+// addProperty adds value to the named property of the item, recording the name in PropertyOrder when first seen.
+func (item *MicrodataItem) addProperty(name string, value any) {
+	existing, ok := item.Properties[name]
+	if !ok {
+		item.PropertyOrder = append(item.PropertyOrder, name)
+	}
+	item.Properties[name] = appendValue(existing, value)
+}
+
+// propertyValue returns the value of the property given by the element n, which has no itemscope attribute. An empty
+// content or datetime attribute is treated as absent, so a <time datetime=""> falls back to its text, and the text of
+// an element without a URL attribute, like a <span itemprop="url">, is kept as is.
+func (p *microdataParser) propertyValue(n *html.Node) string {
+	value := getTextContent(n)
+	attrContent := getAttrVal(n, "content")
+	if attrContent != "" && (n.Data == "meta" || p.opts.MicrodataPreferContent) {
+		value = attrContent
+		if looksLikePath(value) {
+			value = p.resolveHref(value)
 		}
+	} else if datetime := getAttrVal(n, "datetime"); datetime != "" {
+		value = datetime
+	} else if attr, ok := microdataURLAttrs[n.Data]; ok && getAttrVal(n, attr) != "" {
+		value = p.resolveHref(getAttrVal(n, attr))
 	}
+
+	return value
+}
+
+// resolveHref resolves a relative URL value against the base URL of the document. Absolute and protocol-relative URLs
+// are kept as is, and relative ones are left unresolved without a base URL.
+func (p *microdataParser) resolveHref(href string) string {
+	if strings.HasPrefix(href, "//") || strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://") {
+		return href
+	}
+
+	return resolveURL(p.URL, href)
+}
+
+// looksLikePath reports whether the value of a content attribute is a relative URL path, like "/logo.png" or
+// "../logo.png", rather than plain text.
+func looksLikePath(value string) bool {
+	return strings.HasPrefix(value, "/") || strings.HasPrefix(value, "./") || strings.HasPrefix(value, "../")
 }
 
 func getAttr(n *html.Node, key string) bool {
@@ -133,6 +379,8 @@ func getAttrVal(n *html.Node, key string) string {
 	return ""
 }
 
+// appendValue adds value to the existing value of a property, turning it into an []any once the property is given
+// several times.
 func appendValue(existing any, value any) any {
 	if existing == nil {
 		return value
@@ -0,0 +1,666 @@
+package extractor
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestW3CMicrodataWithOptions_preferContent(t *testing.T) {
+	content := microdataFixture(t, "test-42-w3cmicrodata-content-attribute.html")
+
+	tests := []struct {
+		name string
+		opts Options
+		want []MicrodataItem
+	}{
+		{
+			name: "content attribute of meta only",
+			opts: Options{},
+			want: []MicrodataItem{
+				{
+					Type:  "https://schema.org/Offer",
+					Types: []string{"https://schema.org/Offer"},
+					Properties: map[string]any{
+						"price":         "$1,000",
+						"priceCurrency": "US dollars",
+						"availability":  "In stock",
+						"itemCondition": "https://schema.org/NewCondition",
+					},
+					PropertyOrder: []string{"price", "priceCurrency", "availability", "itemCondition"},
+				},
+			},
+		},
+		{
+			name: "content attribute preferred",
+			opts: Options{MicrodataPreferContent: true},
+			want: []MicrodataItem{
+				{
+					Type:  "https://schema.org/Offer",
+					Types: []string{"https://schema.org/Offer"},
+					Properties: map[string]any{
+						"price":         "1000.00",
+						"priceCurrency": "USD",
+						"availability":  "In stock",
+						"itemCondition": "https://schema.org/NewCondition",
+					},
+					PropertyOrder: []string{"price", "priceCurrency", "availability", "itemCondition"},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, errs := W3CMicrodataWithOptions("https://example.com/", content, test.opts)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %+v, got %+v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestW3CMicrodata_mixedValues(t *testing.T) {
+	content := microdataFixture(t, "test-53-w3cmicrodata-mixed-values.html")
+
+	want := []MicrodataItem{
+		{
+			Type:  "https://schema.org/Book",
+			Types: []string{"https://schema.org/Book"},
+			Properties: map[string]any{
+				"name": "The Catcher in the Rye",
+				"author": []any{
+					"Anonymous",
+					&MicrodataItem{
+						Type:  "https://schema.org/Person",
+						Types: []string{"https://schema.org/Person"},
+						Properties: map[string]any{
+							"name": "J.D. Salinger",
+						},
+						PropertyOrder: []string{"name"},
+					},
+				},
+			},
+			PropertyOrder: []string{"name", "author"},
+		},
+	}
+
+	got, errs := W3CMicrodata("https://example.com/", content)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestW3CMicrodata_itemref(t *testing.T) {
+	content := microdataFixture(t, "test-64-w3cmicrodata-itemref.html")
+
+	carol := &MicrodataItem{
+		Type:  "https://schema.org/Person",
+		Types: []string{"https://schema.org/Person"},
+		Properties: map[string]any{
+			"name": "Carol",
+		},
+		PropertyOrder: []string{"name"},
+	}
+	want := []MicrodataItem{
+		{
+			Type:  "https://schema.org/Product",
+			Types: []string{"https://schema.org/Product"},
+			Properties: map[string]any{
+				"name":  "Anvil",
+				"price": "119.99",
+				"aggregateRating": &MicrodataItem{
+					Type:  "https://schema.org/AggregateRating",
+					Types: []string{"https://schema.org/AggregateRating"},
+					Properties: map[string]any{
+						"ratingValue": "4.4",
+						"reviewCount": "89",
+					},
+					PropertyOrder: []string{"ratingValue", "reviewCount"},
+				},
+			},
+			PropertyOrder: []string{"name", "price", "aggregateRating"},
+		},
+		{
+			Type:  "https://schema.org/Person",
+			Types: []string{"https://schema.org/Person"},
+			Properties: map[string]any{
+				"name": "Alice",
+				"spouse": &MicrodataItem{
+					Type:  "https://schema.org/Person",
+					Types: []string{"https://schema.org/Person"},
+					Properties: map[string]any{
+						"name":   "Bob",
+						"spouse": carol,
+					},
+					PropertyOrder: []string{"name", "spouse"},
+				},
+			},
+			PropertyOrder: []string{"name", "spouse"},
+		},
+	}
+
+	got, errs := W3CMicrodata("https://example.com/", content)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestW3CMicrodata_multipleItemprop(t *testing.T) {
+	content := microdataFixture(t, "test-82-w3cmicrodata-multiple-itemprop.html")
+
+	author := &MicrodataItem{
+		Type:  "https://schema.org/Person",
+		Types: []string{"https://schema.org/Person"},
+		Properties: map[string]any{
+			"name": "J. R. R. Tolkien",
+		},
+		PropertyOrder: []string{"name"},
+	}
+	want := []MicrodataItem{
+		{
+			Type:  "https://schema.org/Book",
+			Types: []string{"https://schema.org/Book"},
+			Properties: map[string]any{
+				"name":                "The Hobbit",
+				"alternativeHeadline": "The Hobbit",
+				"url":                 "https://example.com/books/the-hobbit",
+				"sameAs":              "https://example.com/books/the-hobbit",
+				"author":              author,
+				"creator":             author,
+			},
+			PropertyOrder: []string{"name", "alternativeHeadline", "url", "sameAs", "author", "creator"},
+		},
+	}
+
+	got, errs := W3CMicrodata("https://example.com/", content)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestMicrodataItem_MarshalJSON(t *testing.T) {
+	content := microdataFixture(t, "test-34-w3cmicrodata-extended.html")
+
+	items, errs := W3CMicrodata("https://example.com/", content)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(items))
+	}
+
+	got, err := json.Marshal(items[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{"type":"https://schema.org/SoftwareApplication","types":["https://schema.org/SoftwareApplication"],` +
+		`"properties":{"name":"Angry Birds","operatingSystem":"ANDROID","downloadUrl":"https://example.com/download",` +
+		`"applicationCategory":"https://schema.org/SoftwareApplication",` +
+		`"aggregateRating":{"type":"https://schema.org/AggregateRating","types":["https://schema.org/AggregateRating"],` +
+		`"properties":{"ratingValue":"4.6","ratingCount":"8864"}},` +
+		`"offers":{"type":"https://schema.org/Offer","types":["https://schema.org/Offer"],` +
+		`"properties":{"price":"1.00","priceCurrency":"USD"}}}}`
+	if string(got) != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestMicrodataItem_PropertyNames(t *testing.T) {
+	item := MicrodataItem{
+		Properties: map[string]any{
+			"name":   "Anvil",
+			"price":  "119.99",
+			"brand":  "ACME",
+			"weight": "50kg",
+		},
+		PropertyOrder: []string{"price", "name", "missing"},
+	}
+
+	want := []string{"price", "name", "brand", "weight"}
+	if got := item.PropertyNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestW3CMicrodata_typelessItemID(t *testing.T) {
+	content := microdataFixture(t, "test-84-w3cmicrodata-typeless-itemid.html")
+
+	childID := "urn:x"
+	want := []MicrodataItem{
+		{
+			Properties: map[string]any{
+				"label": "Container",
+				"part": &MicrodataItem{
+					ID: &childID,
+					Properties: map[string]any{
+						"name": "Child",
+					},
+					PropertyOrder: []string{"name"},
+				},
+			},
+			PropertyOrder: []string{"label", "part"},
+		},
+	}
+
+	got, errs := W3CMicrodata("https://example.com/", content)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	encoded, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantJSON := `[{"properties":{"label":"Container","part":{"id":"urn:x","properties":{"name":"Child"}}}}]`
+	if string(encoded) != wantJSON {
+		t.Errorf("expected %s, got %s", wantJSON, encoded)
+	}
+}
+
+func TestW3CMicrodata_relativeURLs(t *testing.T) {
+	content := microdataFixture(t, "test-85-w3cmicrodata-relative-urls.html")
+
+	want := []MicrodataItem{
+		{
+			Type:  "https://schema.org/Organization",
+			Types: []string{"https://schema.org/Organization"},
+			Properties: map[string]any{
+				"name":   "ACME",
+				"image":  "https://example.com/company/images/building.jpg",
+				"logo":   "https://example.com/images/logo.png",
+				"sameAs": "https://example.com/social/acme",
+				"slogan": "/ Anvils and more /",
+			},
+			PropertyOrder: []string{"name", "image", "logo", "sameAs", "slogan"},
+		},
+	}
+
+	got, errs := W3CMicrodata("https://example.com/company/about", content)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestW3CMicrodata_plainTextURL(t *testing.T) {
+	content := `<div itemscope itemtype="https://schema.org/VideoObject">
+<span itemprop="url">https://example.com/videos/1</span>
+<div itemprop="contentUrl">/media/1.mp4</div>
+<a itemprop="embedUrl" href="/embed/1">Embed</a>
+</div>`
+
+	want := []MicrodataItem{
+		{
+			Type:  "https://schema.org/VideoObject",
+			Types: []string{"https://schema.org/VideoObject"},
+			Properties: map[string]any{
+				"url":        "https://example.com/videos/1",
+				"contentUrl": "/media/1.mp4",
+				"embedUrl":   "https://example.com/embed/1",
+			},
+			PropertyOrder: []string{"url", "contentUrl", "embedUrl"},
+		},
+	}
+
+	got, errs := W3CMicrodata("https://example.com/videos/", content)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestW3CMicrodata_baseHref(t *testing.T) {
+	content := microdataFixture(t, "test-97-base-href.html")
+
+	want := []MicrodataItem{
+		{
+			Type:  "https://schema.org/Product",
+			Types: []string{"https://schema.org/Product"},
+			Properties: map[string]any{
+				"name":  "Anvil",
+				"image": "https://cdn.example.com/assets/images/anvil.png",
+				"url":   "https://cdn.example.com/products/anvil",
+			},
+			PropertyOrder: []string{"name", "image", "url"},
+		},
+	}
+
+	got, errs := W3CMicrodata("https://example.com/catalog/anvil", content)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestW3CMicrodata_nestedTopLevelItems(t *testing.T) {
+	content := microdataFixture(t, "test-100-w3cmicrodata-nested-top-level.html")
+
+	want := []MicrodataItem{
+		{
+			Type:  "https://schema.org/BlogPosting",
+			Types: []string{"https://schema.org/BlogPosting"},
+			Properties: map[string]any{
+				"headline": "Forging anvils",
+				"author": &MicrodataItem{
+					Type:          "https://schema.org/Person",
+					Types:         []string{"https://schema.org/Person"},
+					Properties:    map[string]any{"name": "Jane Doe"},
+					PropertyOrder: []string{"name"},
+				},
+				"articleBody": "Anvils are forged, not cast.",
+			},
+			PropertyOrder: []string{"headline", "author", "articleBody"},
+		},
+		{
+			Type:  "https://schema.org/Product",
+			Types: []string{"https://schema.org/Product"},
+			Properties: map[string]any{
+				"name": "Anvil",
+				"offers": &MicrodataItem{
+					Type:          "https://schema.org/Offer",
+					Types:         []string{"https://schema.org/Offer"},
+					Properties:    map[string]any{"price": "119.99"},
+					PropertyOrder: []string{"price"},
+				},
+			},
+			PropertyOrder: []string{"name", "offers"},
+		},
+	}
+
+	got, errs := W3CMicrodata("", content)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestW3CMicrodata_time(t *testing.T) {
+	content := microdataFixture(t, "test-86-w3cmicrodata-time.html")
+
+	want := []MicrodataItem{
+		{
+			Type:  "https://schema.org/BlogPosting",
+			Types: []string{"https://schema.org/BlogPosting"},
+			Properties: map[string]any{
+				"headline":      "Release notes",
+				"datePublished": "2020-08-04",
+				"dateModified":  "2020-08-05",
+				"dateCreated":   "2020-08-01T09:00:00Z",
+			},
+			PropertyOrder: []string{"headline", "datePublished", "dateModified", "dateCreated"},
+		},
+	}
+
+	got, errs := W3CMicrodata("https://example.com/", content)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestW3CMicrodata_emptySubitem(t *testing.T) {
+	content := microdataFixture(t, "test-90-w3cmicrodata-empty-subitem.html")
+
+	want := []MicrodataItem{
+		{
+			Type:  "https://schema.org/Book",
+			Types: []string{"https://schema.org/Book"},
+			Properties: map[string]any{
+				"name":   "The Hobbit",
+				"author": "J. R. R. Tolkien",
+				"publisher": &MicrodataItem{
+					Type:       "https://schema.org/Organization",
+					Types:      []string{"https://schema.org/Organization"},
+					Properties: map[string]any{},
+				},
+			},
+			PropertyOrder: []string{"name", "author", "publisher"},
+		},
+	}
+
+	got, errs := W3CMicrodata("https://example.com/", content)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestW3CMicrodata_multipleItemtype(t *testing.T) {
+	content := microdataFixture(t, "test-83-w3cmicrodata-multiple-itemtype.html")
+
+	want := []MicrodataItem{
+		{
+			Type:  "https://schema.org/Product",
+			Types: []string{"https://schema.org/Product", "https://schema.org/Vehicle"},
+			Properties: map[string]any{
+				"name":          "Model S",
+				"vehicleEngine": "Electric",
+			},
+			PropertyOrder: []string{"name", "vehicleEngine"},
+		},
+	}
+
+	got, errs := W3CMicrodata("https://example.com/", content)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestW3CMicrodata_attributeValues(t *testing.T) {
+	content := microdataFixture(t, "test-65-w3cmicrodata-media.html")
+
+	tests := []struct {
+		name string
+		URL  string
+		want map[string]any
+	}{
+		{
+			name: "with page URL",
+			URL:  "https://example.com/videos/anvil.html",
+			want: map[string]any{
+				"name":            "Anvil unboxing",
+				"duration":        "PT3M14S",
+				"thumbnailUrl":    "https://example.com/img/thumbnail.jpg",
+				"image":           "https://cdn.example.com/anvil.jpg",
+				"contentUrl":      "https://example.com/videos/media/anvil.mp4",
+				"embedUrl":        "//player.example.com/embed/anvil",
+				"associatedMedia": "https://example.com/videos/media/anvil.swf",
+				"license":         "https://example.com/license.html",
+				"caption":         "",
+			},
+		},
+		{
+			name: "without page URL",
+			URL:  "",
+			want: map[string]any{
+				"name":            "Anvil unboxing",
+				"duration":        "PT3M14S",
+				"thumbnailUrl":    "/img/thumbnail.jpg",
+				"image":           "https://cdn.example.com/anvil.jpg",
+				"contentUrl":      "media/anvil.mp4",
+				"embedUrl":        "//player.example.com/embed/anvil",
+				"associatedMedia": "media/anvil.swf",
+				"license":         "/license.html",
+				"caption":         "",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, errs := W3CMicrodata(test.URL, content)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if len(got) != 1 {
+				t.Fatalf("expected one item, got %+v", got)
+			}
+			if !reflect.DeepEqual(got[0].Properties, test.want) {
+				t.Errorf("expected %+v, got %+v", test.want, got[0].Properties)
+			}
+		})
+	}
+}
+
+func TestMicrodataItem_Values(t *testing.T) {
+	person := &MicrodataItem{Type: "https://schema.org/Person"}
+	item := MicrodataItem{
+		Properties: map[string]any{
+			"name":   "The Catcher in the Rye",
+			"author": []any{"Anonymous", person},
+			"editor": person,
+		},
+		PropertyOrder: []string{"name", "author", "editor"},
+	}
+
+	tests := []struct {
+		name string
+		want []any
+	}{
+		{
+			name: "name",
+			want: []any{"The Catcher in the Rye"},
+		},
+		{
+			name: "author",
+			want: []any{"Anonymous", person},
+		},
+		{
+			name: "editor",
+			want: []any{person},
+		},
+		{
+			name: "missing",
+			want: nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := item.Values(test.name); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func Test_appendValue(t *testing.T) {
+	person := &MicrodataItem{Type: "https://schema.org/Person"}
+
+	tests := []struct {
+		name     string
+		existing any
+		value    any
+		want     any
+	}{
+		{
+			name:     "first value",
+			existing: nil,
+			value:    "Anonymous",
+			want:     "Anonymous",
+		},
+		{
+			name:     "item after literal",
+			existing: "Anonymous",
+			value:    person,
+			want:     []any{"Anonymous", person},
+		},
+		{
+			name:     "literal after item",
+			existing: person,
+			value:    "Anonymous",
+			want:     []any{person, "Anonymous"},
+		},
+		{
+			name:     "item after list",
+			existing: []any{"Anonymous", "Unknown"},
+			value:    person,
+			want:     []any{"Anonymous", "Unknown", person},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := appendValue(test.existing, test.value); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func Test_parseW3CMicrodataReader_partial(t *testing.T) {
+	readErr := errors.New("connection reset")
+	r := io.MultiReader(
+		strings.NewReader(`<html><body>
+<div itemscope itemtype="https://schema.org/Person"><span itemprop="name">Jane Doe</span></div>
+<div itemscope itemtype="https://schema.org/Person"><span itemprop="name">John`),
+		iotest.ErrReader(readErr),
+	)
+
+	items, errs := parseW3CMicrodataReader("https://example.com/", r, Options{})
+	if len(errs) != 1 || !errors.Is(errs[0], readErr) {
+		t.Errorf("expected the read error to be recorded, got %v", errs)
+	}
+
+	want := []*MicrodataItem{
+		{
+			Type:          "https://schema.org/Person",
+			Types:         []string{"https://schema.org/Person"},
+			Properties:    map[string]any{"name": "Jane Doe"},
+			PropertyOrder: []string{"name"},
+		},
+		{
+			Type:          "https://schema.org/Person",
+			Types:         []string{"https://schema.org/Person"},
+			Properties:    map[string]any{"name": "John"},
+			PropertyOrder: []string{"name"},
+		},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("expected %+v, got %+v", want, items)
+	}
+}
+
+// microdataFixture returns the content of a test fixture.
+func microdataFixture(t *testing.T, name string) string {
+	t.Helper()
+
+	content, err := os.ReadFile("../test/" + name)
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	return string(content)
+}
@@ -1,11 +1,14 @@
 package extractor
 
 import (
+	"encoding/json"
 	"fmt"
 	"golang.org/x/net/html"
 	"io"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type XCards struct {
@@ -26,6 +29,10 @@ type XCards struct {
 	LocaleAlternate []string `json:"twitter:locale:alternate,omitempty"`
 	SiteName        string   `json:"twitter:site_name,omitempty"`
 
+	// Freshness, filled from Open Graph
+	UpdatedTime time.Time `json:"og:updated_time,omitempty"`
+	TTL         int       `json:"og:ttl,omitempty"`
+
 	// Media
 	OpenGraphImage []OpenGraphImage `json:"og:image,omitempty"`
 	OpenGraphAudio []OpenGraphAudio `json:"og:audio,omitempty"`
@@ -34,6 +41,15 @@ type XCards struct {
 	XCardsAudio    []XCardsAudio    `json:"twitter:audio,omitempty"`
 	XCardsVideo    []XCardsVideo    `json:"twitter:video,omitempty"`
 
+	// Player card
+	Player *XCardsPlayer `json:"twitter:player,omitempty"`
+
+	// App card
+	App *XCardsApp `json:"twitter:app,omitempty"`
+
+	// Custom data rows, like twitter:label1 and twitter:data1, by index
+	Labels []XCardsLabel `json:"twitter:labels,omitempty"`
+
 	// Music specific
 	Music *Music `json:"music,omitempty"`
 
@@ -48,6 +64,23 @@ type XCards struct {
 
 	// Profile specific
 	Profile *Profile `json:"profile,omitempty"`
+
+	// Product specific
+	Product *OpenGraphProduct `json:"product,omitempty"`
+}
+
+// MarshalJSON encodes the cards like their fields would be, leaving out og:updated_time if it is the zero time, like
+// OpenGraph does.
+func (xc XCards) MarshalJSON() ([]byte, error) {
+	type xCards XCards
+	if !xc.UpdatedTime.IsZero() {
+		return json.Marshal(xCards(xc))
+	}
+
+	return json.Marshal(struct {
+		xCards
+		UpdatedTime *time.Time `json:"og:updated_time,omitempty"`
+	}{xCards: xCards(xc)})
 }
 
 // XCardsImage represents XCards image object
@@ -76,17 +109,62 @@ type XCardsAudio struct {
 	Type      string `json:"twitter:audio:type,omitempty"`
 }
 
+// XCardsPlayer represents the player of a player card
+type XCardsPlayer struct {
+	URL    string `json:"twitter:player"`
+	Width  int    `json:"twitter:player:width,omitempty"`
+	Height int    `json:"twitter:player:height,omitempty"`
+	Stream string `json:"twitter:player:stream,omitempty"`
+}
+
+// XCardsApp represents the apps of an app card by platform
+type XCardsApp struct {
+	IPhone     *XCardsAppPlatform `json:"iphone,omitempty"`
+	IPad       *XCardsAppPlatform `json:"ipad,omitempty"`
+	GooglePlay *XCardsAppPlatform `json:"googleplay,omitempty"`
+}
+
+// XCardsAppPlatform represents the app of an app card on a platform
+type XCardsAppPlatform struct {
+	Name string `json:"name,omitempty"`
+	ID   string `json:"id,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// XCardsLabel represents a custom data row of a card, given by twitter:label<n> and twitter:data<n>
+type XCardsLabel struct {
+	Label string `json:"label,omitempty"`
+	Data  string `json:"data,omitempty"`
+}
+
+// maxXCardsLabels limits the index of the custom data rows, as X displays two of them.
+const maxXCardsLabels = 10
+
 // NewXCards creates a new XCards instance with basic initialization
 func NewXCards() *XCards {
 	return &XCards{}
 }
 
 func ParseXCards(URL string, htmlContent string) (any, []error) {
-	_ = URL
-	itemXCards, errorsXCards := extractXCards(htmlContent)
+	return ParseXCardsWithOptions(URL, htmlContent, Options{})
+}
+
+// ParseXCardsWithOptions extracts the X Cards of the HTML content like ParseXCards, using the given parser options.
+func ParseXCardsWithOptions(URL string, htmlContent string, opts Options) (any, []error) {
+	itemXCards, errorsXCards := extractXCards(htmlContent, opts.OpenGraphStrict)
+	if opts.XCardsSkipOpenGraph {
+		if itemXCards == nil {
+			return nil, errorsXCards
+		}
+		return itemXCards, errorsXCards
+	}
 
-	itemOpenGraph, errorsOpenGraph := extractOpenGraph(htmlContent)
+	itemOpenGraph, errorsOpenGraph := extractOpenGraph(htmlContent, false)
 	if itemOpenGraph != nil {
+		resolveOpenGraphURLs(itemOpenGraph, documentBaseURL(URL, htmlContent))
+		if opts.OpenGraphDedupeMedia {
+			dedupeOpenGraphMedia(itemOpenGraph)
+		}
 		if itemXCards == nil {
 			itemXCards = &XCards{}
 		}
@@ -102,13 +180,16 @@ func ParseXCards(URL string, htmlContent string) (any, []error) {
 	return results, append(errorsXCards, errorsOpenGraph...)
 }
 
-func extractXCards(htmlContent string) (*XCards, []error) {
+// extractXCards extracts the X Cards of the HTML content, validating the book:isbn values if strict is set, like
+// the Open Graph ones.
+func extractXCards(htmlContent string, strict bool) (*XCards, []error) {
 	var errors []error
 
 	xc := NewXCards()
 	tokenizer := html.NewTokenizer(strings.NewReader(htmlContent))
 
 	xcHasValue := false
+	afterAuthor := false
 	for {
 		if tokenizer.Err() == io.EOF {
 			break
@@ -136,7 +217,18 @@ func extractXCards(htmlContent string) (*XCards, []error) {
 				}
 			}
 			if property != "" && content != "" {
-				parseXCardsMetaTag(xc, property, content)
+				if afterAuthor && strings.HasPrefix(property, "profile:") {
+					handleArticleAuthorProfileProperty(xc.Article, property, content)
+				} else {
+					parseXCardsMetaTag(xc, property, content)
+				}
+				// the profile: properties directly following an article:author describe that author, like in Open Graph
+				afterAuthor = property == "article:author" || (afterAuthor && strings.HasPrefix(property, "profile:"))
+				if strict && property == "book:isbn" {
+					if err := validateISBN(property, content); err != nil {
+						errors = append(errors, err)
+					}
+				}
 				xcHasValue = true
 			}
 		default:
@@ -196,6 +288,18 @@ func parseXCardsMetaTag(xc *XCards, property, content string) {
 	case strings.HasPrefix(property, "twitter:audio"):
 		handleXCardsAudioProperty(xc, parts, content)
 
+	// Player card handling
+	case strings.HasPrefix(property, "twitter:player"):
+		handleXCardsPlayerProperty(xc, property, content)
+
+	// App card handling, like twitter:app:name:iphone
+	case strings.HasPrefix(property, "twitter:app:"):
+		handleXCardsAppProperty(xc, parts, content)
+
+	// Custom data rows handling
+	case strings.HasPrefix(property, "twitter:label"), strings.HasPrefix(property, "twitter:data"):
+		handleXCardsLabelProperty(xc, property, content)
+
 	// Music handling with multi-level properties
 	case strings.HasPrefix(property, "music:"):
 		if xc.Music == nil {
@@ -218,7 +322,7 @@ func parseXCardsMetaTag(xc *XCards, property, content string) {
 		case property == "music:creator":
 			xc.Music.Creator = append(xc.Music.Creator, content)
 		case property == "music:release_date":
-			xc.Music.ReleaseDate = content
+			xc.Music.ReleaseDate = parseTimeSafely(content)
 		}
 
 	// Video handling with multi-level properties
@@ -271,7 +375,7 @@ func parseXCardsMetaTag(xc *XCards, property, content string) {
 		}
 		switch property {
 		case "book:isbn":
-			xc.Book.ISBN = content
+			xc.Book.ISBN = normalizeISBN(content)
 		case "book:release_date":
 			xc.Book.ReleaseDate = parseTimeSafely(content)
 		case "book:author":
@@ -280,29 +384,25 @@ func parseXCardsMetaTag(xc *XCards, property, content string) {
 			xc.Book.Tag = append(xc.Book.Tag, content)
 		}
 
-	// Profile handling remains the same
+	// Profile handling, the profile: properties following an article:author are handled by extractXCards
 	case strings.HasPrefix(property, "profile:"):
 		if xc.Profile == nil {
 			xc.Profile = &Profile{}
 		}
-		switch property {
-		case "profile:first_name":
-			xc.Profile.FirstName = content
-		case "profile:last_name":
-			xc.Profile.LastName = content
-		case "profile:username":
-			xc.Profile.Username = content
-		case "profile:gender":
-			xc.Profile.Gender = content
+		setProfileProperty(xc.Profile, property, content)
+
+	// Product handling
+	case strings.HasPrefix(property, "product:"):
+		if xc.Product == nil {
+			xc.Product = &OpenGraphProduct{}
 		}
+		setProductProperty(xc.Product, property, content)
 	}
 }
 
 func handleXCardsImageProperty(xc *XCards, parts []string, content string) {
-	if len(xc.XCardsImage) == 0 || parts[1] == "image" {
-		if len(parts) < 3 {
-			xc.XCardsImage = append(xc.XCardsImage, XCardsImage{})
-		}
+	if n := len(xc.XCardsImage); n == 0 || startsNewMediaElement(parts, xc.XCardsImage[n-1].URL, xc.XCardsImage[n-1].SecureURL) {
+		xc.XCardsImage = append(xc.XCardsImage, XCardsImage{})
 	}
 	lastIdx := len(xc.XCardsImage) - 1
 
@@ -312,6 +412,8 @@ func handleXCardsImageProperty(xc *XCards, parts []string, content string) {
 	}
 
 	switch parts[2] {
+	case "url":
+		xc.XCardsImage[lastIdx].URL = content
 	case "secure_url":
 		xc.XCardsImage[lastIdx].SecureURL = content
 	case "type":
@@ -326,10 +428,8 @@ func handleXCardsImageProperty(xc *XCards, parts []string, content string) {
 }
 
 func handleXCardsVideoProperty(xc *XCards, parts []string, content string) {
-	if len(xc.XCardsVideo) == 0 || parts[1] == "video" {
-		if len(parts) < 3 {
-			xc.XCardsVideo = append(xc.XCardsVideo, XCardsVideo{})
-		}
+	if n := len(xc.XCardsVideo); n == 0 || startsNewMediaElement(parts, xc.XCardsVideo[n-1].URL, xc.XCardsVideo[n-1].SecureURL) {
+		xc.XCardsVideo = append(xc.XCardsVideo, XCardsVideo{})
 	}
 	lastIdx := len(xc.XCardsVideo) - 1
 
@@ -339,6 +439,8 @@ func handleXCardsVideoProperty(xc *XCards, parts []string, content string) {
 	}
 
 	switch parts[2] {
+	case "url":
+		xc.XCardsVideo[lastIdx].URL = content
 	case "secure_url":
 		xc.XCardsVideo[lastIdx].SecureURL = content
 	case "type":
@@ -351,10 +453,8 @@ func handleXCardsVideoProperty(xc *XCards, parts []string, content string) {
 }
 
 func handleXCardsAudioProperty(xc *XCards, parts []string, content string) {
-	if len(xc.XCardsAudio) == 0 || parts[1] == "audio" {
-		if len(parts) < 3 {
-			xc.XCardsAudio = append(xc.XCardsAudio, XCardsAudio{})
-		}
+	if n := len(xc.XCardsAudio); n == 0 || startsNewMediaElement(parts, xc.XCardsAudio[n-1].URL, xc.XCardsAudio[n-1].SecureURL) {
+		xc.XCardsAudio = append(xc.XCardsAudio, XCardsAudio{})
 	}
 	lastIdx := len(xc.XCardsAudio) - 1
 
@@ -364,6 +464,8 @@ func handleXCardsAudioProperty(xc *XCards, parts []string, content string) {
 	}
 
 	switch parts[2] {
+	case "url":
+		xc.XCardsAudio[lastIdx].URL = content
 	case "secure_url":
 		xc.XCardsAudio[lastIdx].SecureURL = content
 	case "type":
@@ -371,6 +473,86 @@ func handleXCardsAudioProperty(xc *XCards, parts []string, content string) {
 	}
 }
 
+func handleXCardsPlayerProperty(xc *XCards, property, content string) {
+	if xc.Player == nil {
+		xc.Player = &XCardsPlayer{}
+	}
+
+	switch property {
+	case "twitter:player":
+		xc.Player.URL = content
+	case "twitter:player:width":
+		xc.Player.Width = parseIntSafely(content)
+	case "twitter:player:height":
+		xc.Player.Height = parseIntSafely(content)
+	case "twitter:player:stream":
+		xc.Player.Stream = content
+	}
+}
+
+// handleXCardsAppProperty applies a twitter:app:<field>:<platform> property, split into parts, to the app of the
+// platform. Properties with an unknown field or platform are ignored.
+func handleXCardsAppProperty(xc *XCards, parts []string, content string) {
+	if len(parts) != 4 {
+		return
+	}
+
+	app := xc.App
+	if app == nil {
+		app = &XCardsApp{}
+	}
+	var platform **XCardsAppPlatform
+	switch parts[3] {
+	case "iphone":
+		platform = &app.IPhone
+	case "ipad":
+		platform = &app.IPad
+	case "googleplay":
+		platform = &app.GooglePlay
+	default:
+		return
+	}
+	p := *platform
+	if p == nil {
+		p = &XCardsAppPlatform{}
+	}
+
+	switch parts[2] {
+	case "name":
+		p.Name = content
+	case "id":
+		p.ID = content
+	case "url":
+		p.URL = content
+	default:
+		return
+	}
+
+	*platform = p
+	xc.App = app
+}
+
+// handleXCardsLabelProperty sets the label or the data of the custom data row given by the index of a twitter:label<n>
+// or twitter:data<n> property, so labels and data pair by index regardless of their order. Properties with an index
+// out of 1..maxXCardsLabels are ignored.
+func handleXCardsLabelProperty(xc *XCards, property, content string) {
+	name := strings.TrimPrefix(property, "twitter:")
+	key := strings.TrimRight(name, "0123456789")
+	index, err := strconv.Atoi(name[len(key):])
+	if (key != "label" && key != "data") || err != nil || index < 1 || index > maxXCardsLabels {
+		return
+	}
+
+	for len(xc.Labels) < index {
+		xc.Labels = append(xc.Labels, XCardsLabel{})
+	}
+	if key == "label" {
+		xc.Labels[index-1].Label = content
+	} else {
+		xc.Labels[index-1].Data = content
+	}
+}
+
 // fillMissingFieldsFromOpenGraph fills missing fields in the target struct with values from the source struct.
 func fillMissingFieldsFromOpenGraph(target, source any) []error {
 	var errors []error
@@ -406,16 +588,27 @@ func fillMissingFieldsFromOpenGraph(target, source any) []error {
 			}
 		case reflect.Ptr:
 			if tField.IsNil() && !sField.IsNil() {
-				tField.Set(sField)
+				tField.Set(deepCopy(sField))
 			} else if !tField.IsNil() && !sField.IsNil() {
 				errs := fillMissingFieldsFromOpenGraph(tField.Interface(), sField.Interface())
 				errors = append(errors, errs...)
 			}
 		case reflect.Slice:
 			if tField.IsNil() && sField.Len() > 0 {
+				tField.Set(deepCopy(sField))
+			}
+		case reflect.Int:
+			// only og:ttl is mirrored, the dimensions and durations of X Cards are their own
+			if sFieldName == "TTL" && tField.Int() == 0 {
 				tField.Set(sField)
 			}
 		case reflect.Struct:
+			if t, ok := tField.Interface().(time.Time); ok {
+				if t.IsZero() {
+					tField.Set(sField)
+				}
+				continue
+			}
 			errs := fillMissingFieldsFromOpenGraph(tField.Addr().Interface(), sField.Addr().Interface())
 			errors = append(errors, errs...)
 		default:
@@ -425,3 +618,47 @@ func fillMissingFieldsFromOpenGraph(target, source any) []error {
 
 	return errors
 }
+
+// deepCopy returns a copy of v sharing no pointer, slice or map with it, so the X Cards filled from Open Graph stay
+// independent of it. The unexported fields of structs, like the location of a time.Time, are copied as they are.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(deepCopy(v.Elem()))
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return c
+	}
+
+	return v
+}
@@ -0,0 +1,219 @@
+package extractor
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_fillMissingFieldsFromOpenGraph_independent(t *testing.T) {
+	og := &OpenGraph{
+		Title:           "Title",
+		LocaleAlternate: []string{"en_GB"},
+		OpenGraphImage:  []OpenGraphImage{{URL: "https://example.com/a.jpg"}},
+		Article: &Article{
+			Author: []string{"https://example.com/jane"},
+			AuthorProfile: []ArticleAuthor{
+				{URL: "https://example.com/jane", Profile: Profile{FirstName: "Jane"}},
+			},
+		},
+	}
+	xc := &XCards{}
+	if errs := fillMissingFieldsFromOpenGraph(xc, og); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	want := &XCards{
+		Title:           "Title",
+		LocaleAlternate: []string{"en_GB"},
+		OpenGraphImage:  []OpenGraphImage{{URL: "https://example.com/a.jpg"}},
+		Article: &Article{
+			Author: []string{"https://example.com/jane"},
+			AuthorProfile: []ArticleAuthor{
+				{URL: "https://example.com/jane", Profile: Profile{FirstName: "Jane"}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(xc, want) {
+		t.Fatalf("expected %+v, got %+v", want, xc)
+	}
+
+	og.LocaleAlternate[0] = "de_DE"
+	og.OpenGraphImage[0].URL = "https://example.com/b.jpg"
+	og.Article.Author[0] = "https://example.com/john"
+	og.Article.AuthorProfile[0].FirstName = "John"
+	og.Article.Section = "Sports"
+
+	if !reflect.DeepEqual(xc, want) {
+		t.Errorf("expected the X Cards to be unchanged, got %+v", xc)
+	}
+}
+
+func Test_fillMissingFieldsFromOpenGraph_ints(t *testing.T) {
+	og := &OpenGraph{
+		TTL:   3600,
+		Video: &Video{Duration: 120, Series: "https://example.com/series"},
+	}
+	xc := &XCards{Video: &Video{Tag: []string{"anvils"}}}
+	if errs := fillMissingFieldsFromOpenGraph(xc, og); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	want := &XCards{
+		TTL:   3600,
+		Video: &Video{Tag: []string{"anvils"}, Series: "https://example.com/series"},
+	}
+	if !reflect.DeepEqual(xc, want) {
+		t.Errorf("expected only og:ttl among the integers to be filled, got %+v", xc)
+	}
+}
+
+func TestParseXCards_mediaURLAlias(t *testing.T) {
+	content, err := os.ReadFile("../test/test-102-media-url-alias.html")
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	got, errs := ParseXCardsWithOptions("", string(content), Options{XCardsSkipOpenGraph: true})
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	xc := got.(*XCards)
+	wantImages := []XCardsImage{
+		{URL: "https://example.com/img/first.jpg", Alt: "First"},
+		{URL: "https://example.com/img/second.jpg", Alt: "Second"},
+	}
+	if !reflect.DeepEqual(xc.XCardsImage, wantImages) {
+		t.Errorf("expected images %+v, got %+v", wantImages, xc.XCardsImage)
+	}
+	wantVideos := []XCardsVideo{
+		{URL: "https://example.com/media/first.mp4"},
+		{URL: "https://example.com/media/second.mp4"},
+	}
+	if !reflect.DeepEqual(xc.XCardsVideo, wantVideos) {
+		t.Errorf("expected videos %+v, got %+v", wantVideos, xc.XCardsVideo)
+	}
+	wantAudios := []XCardsAudio{
+		{URL: "https://example.com/media/first.mp3"},
+		{URL: "https://example.com/media/second.mp3"},
+	}
+	if !reflect.DeepEqual(xc.XCardsAudio, wantAudios) {
+		t.Errorf("expected audios %+v, got %+v", wantAudios, xc.XCardsAudio)
+	}
+}
+
+func TestXCards_MarshalJSON(t *testing.T) {
+	encoded, err := json.Marshal(&XCards{Card: "summary"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `{"twitter:card":"summary"}`; string(encoded) != want {
+		t.Errorf("expected %s, got %s", want, encoded)
+	}
+
+	updated := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	encoded, err = json.Marshal(XCards{Card: "summary", UpdatedTime: updated})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `{"twitter:card":"summary","og:updated_time":"2025-01-02T03:04:05Z"}`; string(encoded) != want {
+		t.Errorf("expected %s, got %s", want, encoded)
+	}
+}
+
+func TestParseXCards_articleAuthorProfileAfterOtherTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		opts    Options
+	}{
+		{
+			name: "X Cards tags",
+			content: `<html><head>
+<meta name="twitter:card" content="summary">
+<meta name="article:author" content="https://www.example.com/profileAuthorA.html">
+<meta name="profile:first_name" content="John">
+<meta name="article:section" content="Front page">
+<meta name="twitter:title" content="Title">
+<meta name="profile:username" content="site-owner">
+</head></html>`,
+			opts: Options{XCardsSkipOpenGraph: true},
+		},
+		{
+			name: "filled from Open Graph",
+			content: `<html><head>
+<meta property="og:type" content="article">
+<meta property="article:author" content="https://www.example.com/profileAuthorA.html">
+<meta property="profile:first_name" content="John">
+<meta property="article:section" content="Front page">
+<meta property="og:title" content="Title">
+<meta property="profile:username" content="site-owner">
+</head></html>`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, errs := ParseXCardsWithOptions("", test.content, test.opts)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+
+			xc := got.(*XCards)
+			wantProfiles := []ArticleAuthor{
+				{URL: "https://www.example.com/profileAuthorA.html", Profile: Profile{FirstName: "John"}},
+			}
+			if xc.Article == nil || !reflect.DeepEqual(xc.Article.AuthorProfile, wantProfiles) {
+				t.Errorf("expected author profiles %+v, got %+v", wantProfiles, xc.Article)
+			}
+			if want := (&Profile{Username: "site-owner"}); !reflect.DeepEqual(xc.Profile, want) {
+				t.Errorf("expected the profile of the page %+v, got %+v", want, xc.Profile)
+			}
+		})
+	}
+}
+
+func TestParseXCardsWithOptions_isbn(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		isbn    string
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			content: `<meta name="twitter:card" content="summary"><meta name="book:isbn" content="978-0-306-40615-7">`,
+			isbn:    "9780306406157",
+		},
+		{
+			name:    "invalid",
+			content: `<meta name="twitter:card" content="summary"><meta name="book:isbn" content="978 0 306 40615 8">`,
+			isbn:    "9780306406158",
+			wantErr: `book:isbn "978 0 306 40615 8" is not a valid ISBN-10 or ISBN-13`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, errs := ParseXCards("", test.content)
+			if len(errs) > 0 {
+				t.Fatalf("expected no errors when not strict, got %v", errs)
+			}
+			if isbn := got.(*XCards).Book.ISBN; isbn != test.isbn {
+				t.Errorf("expected ISBN %q, got %q", test.isbn, isbn)
+			}
+
+			_, errs = ParseXCardsWithOptions("", test.content, Options{OpenGraphStrict: true})
+			if test.wantErr == "" {
+				if len(errs) > 0 {
+					t.Errorf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !errors.Is(errs[0], ErrInvalidOpenGraphValue) || !strings.Contains(errs[0].Error(), test.wantErr) {
+				t.Errorf("expected an error naming %s, got %v", test.wantErr, errs)
+			}
+		})
+	}
+}
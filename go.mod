@@ -2,4 +2,8 @@ module github.com/aafeher/go-microdata-extract
 
 go 1.18
 
-require golang.org/x/net v0.31.0
+require (
+	github.com/andybalholm/brotli v1.1.1
+	golang.org/x/net v0.31.0
+	golang.org/x/text v0.20.0 // indirect
+)
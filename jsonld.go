@@ -0,0 +1,68 @@
+package extract
+
+import (
+	"encoding/json"
+	"fmt"
+	extractor "github.com/aafeher/go-microdata-extract/extractors"
+	"reflect"
+)
+
+// JSONLDByType returns the extracted JSON-LD nodes of the given type, including the members of @graph arrays kept
+// with SetJSONLDPassthrough. The @type may be a string or an array; types are compared case-sensitively, with the
+// schema.org prefix removed, so "Product" and "https://schema.org/Product" both match.
+func (e *Extractor) JSONLDByType(t string) []map[string]any {
+	nodes, _ := e.extracted[SyntaxJSONLD].([]map[string]any)
+	t = extractor.NormalizeSchemaType(t)
+
+	var matches []map[string]any
+	for _, node := range jsonLDGraphNodes(nodes) {
+		if contains(extractor.JSONLDTypes(node), t) {
+			matches = append(matches, node)
+		}
+	}
+
+	return matches
+}
+
+// JSONLDBlocks returns the JSON-LD nodes of the extracted page along with the position of their <script> elements, in
+// document order. The content is parsed on demand with the JSON-LD settings of the Extractor.
+func (e *Extractor) JSONLDBlocks() []extractor.JSONLDBlock {
+	blocks, _ := extractor.JSONLDBlocksWithOptions(e.finalURL, e.content, e.cfg.parserOptions)
+
+	return blocks
+}
+
+// DecodeJSONLD decodes the extracted JSON-LD nodes into target, a pointer to a slice of structs with json tags, or to
+// a single struct when exactly one node was extracted. Returns an error wrapping ErrNoJSONLD if no JSON-LD was
+// extracted, or an error if target is not a non-nil pointer.
+func (e *Extractor) DecodeJSONLD(target any) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return fmt.Errorf("decoding JSON-LD: target must be a non-nil pointer, got %T", target)
+	}
+
+	nodes, _ := e.extracted[SyntaxJSONLD].([]map[string]any)
+	if len(nodes) == 0 {
+		return fmt.Errorf("decoding JSON-LD: %w", ErrNoJSONLD)
+	}
+
+	var data any = nodes
+	switch value.Elem().Kind() {
+	case reflect.Slice, reflect.Array, reflect.Interface:
+	default:
+		if len(nodes) > 1 {
+			return fmt.Errorf("decoding JSON-LD: %d nodes extracted, the target must be a pointer to a slice, got %T", len(nodes), target)
+		}
+		data = nodes[0]
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("decoding JSON-LD: %w", err)
+	}
+	if err = json.Unmarshal(encoded, target); err != nil {
+		return fmt.Errorf("decoding JSON-LD: %w", err)
+	}
+
+	return nil
+}
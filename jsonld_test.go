@@ -0,0 +1,159 @@
+package extract
+
+import (
+	"errors"
+	extractor "github.com/aafeher/go-microdata-extract/extractors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testProduct struct {
+	Type  string `json:"@type"`
+	Name  string `json:"name"`
+	Offer struct {
+		Price string `json:"price"`
+	} `json:"offers"`
+}
+
+func TestExtractor_DecodeJSONLD(t *testing.T) {
+	product := map[string]any{"@type": "Product", "name": "Anvil", "offers": map[string]any{"price": "119.99"}}
+	organization := map[string]any{"@type": "Organization", "name": "ACME"}
+
+	t.Run("slice", func(t *testing.T) {
+		e := &Extractor{extracted: map[Syntax]any{SyntaxJSONLD: []map[string]any{product, organization}}}
+
+		var got []testProduct
+		if err := e.DecodeJSONLD(&got); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []testProduct{{Type: "Product", Name: "Anvil"}, {Type: "Organization", Name: "ACME"}}
+		want[0].Offer.Price = "119.99"
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %+v, got %+v", want, got)
+		}
+	})
+
+	t.Run("single struct", func(t *testing.T) {
+		e := &Extractor{extracted: map[Syntax]any{SyntaxJSONLD: []map[string]any{product}}}
+
+		var got testProduct
+		if err := e.DecodeJSONLD(&got); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Name != "Anvil" || got.Offer.Price != "119.99" {
+			t.Errorf("expected the product, got %+v", got)
+		}
+	})
+
+	tests := []struct {
+		name      string
+		extracted map[Syntax]any
+		target    any
+		wantErr   error
+	}{
+		{
+			name:      "no JSON-LD",
+			extracted: map[Syntax]any{SyntaxJSONLD: []map[string]any(nil)},
+			target:    &[]testProduct{},
+			wantErr:   ErrNoJSONLD,
+		},
+		{
+			name:      "JSON-LD not extracted",
+			extracted: map[Syntax]any{},
+			target:    &[]testProduct{},
+			wantErr:   ErrNoJSONLD,
+		},
+		{
+			name:      "not a pointer",
+			extracted: map[Syntax]any{SyntaxJSONLD: []map[string]any{product}},
+			target:    testProduct{},
+		},
+		{
+			name:      "nil pointer",
+			extracted: map[Syntax]any{SyntaxJSONLD: []map[string]any{product}},
+			target:    (*testProduct)(nil),
+		},
+		{
+			name:      "single struct of several nodes",
+			extracted: map[Syntax]any{SyntaxJSONLD: []map[string]any{product, organization}},
+			target:    &testProduct{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := &Extractor{extracted: test.extracted}
+			err := e.DecodeJSONLD(test.target)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if test.wantErr != nil && !errors.Is(err, test.wantErr) {
+				t.Errorf("expected an error wrapping %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestExtractor_JSONLDByType(t *testing.T) {
+	product := map[string]any{"@type": "Product", "name": "Anvil"}
+	multiTyped := map[string]any{"@type": []any{"https://schema.org/Product", "Thing"}, "name": "Hammer"}
+	graphProduct := map[string]any{"@type": "schema:Product", "name": "Tongs"}
+	breadcrumbs := map[string]any{"@type": "BreadcrumbList"}
+	graph := map[string]any{"@graph": []any{graphProduct, map[string]any{"@type": "Organization"}}}
+	e := &Extractor{extracted: map[Syntax]any{SyntaxJSONLD: []map[string]any{breadcrumbs, product, multiTyped, graph}}}
+
+	tests := []struct {
+		name string
+		t    string
+		want []map[string]any
+	}{
+		{
+			name: "type",
+			t:    "Product",
+			want: []map[string]any{product, multiTyped, graphProduct},
+		},
+		{
+			name: "type with schema.org prefix",
+			t:    "https://schema.org/Product",
+			want: []map[string]any{product, multiTyped, graphProduct},
+		},
+		{
+			name: "case-sensitive",
+			t:    "product",
+			want: nil,
+		},
+		{
+			name: "missing type",
+			t:    "Recipe",
+			want: nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := e.JSONLDByType(test.t); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestExtractor_JSONLDBlocks(t *testing.T) {
+	content := `<html><head>
+<script type="application/ld+json">{"@type": "Organization"}</script>
+</head><body>
+<script type="application/ld+json">{"@type": "Product"}</script>
+</body></html>`
+
+	e, err := New().SetSyntaxes([]Syntax{SyntaxOpenGraph}).Extract("https://example.com/", &content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []extractor.JSONLDBlock{
+		{Node: map[string]any{"@type": "Organization"}, Offset: strings.Index(content, "<script"), Line: 2},
+		{Node: map[string]any{"@type": "Product"}, Offset: strings.LastIndex(content, "<script"), Line: 4},
+	}
+	if got := e.JSONLDBlocks(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
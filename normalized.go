@@ -0,0 +1,212 @@
+package extract
+
+import (
+	extractor "github.com/aafeher/go-microdata-extract/extractors"
+	"time"
+)
+
+// PageMetadata represents the basic metadata of a page, merged from the extracted syntaxes, see Normalized.
+type PageMetadata struct {
+	Title         string    `json:"title,omitempty"`
+	Description   string    `json:"description,omitempty"`
+	Images        []string  `json:"images,omitempty"`
+	URL           string    `json:"url,omitempty"`
+	SiteName      string    `json:"site_name,omitempty"`
+	PublishedTime time.Time `json:"published_time,omitempty"`
+
+	// Provenance holds the syntax each field was taken from, keyed by the JSON name of the field, like "title".
+	Provenance map[string]Syntax `json:"provenance,omitempty"`
+}
+
+// normalizedSources lists the syntaxes merged by Normalized, in order of precedence.
+var normalizedSources = []Syntax{SyntaxJSONLD, SyntaxMicrodata, SyntaxOpenGraph, SyntaxXCards, SyntaxHTML}
+
+// normalizedSkippedTypes lists the schema.org types describing something else than the page itself, whose nodes and
+// items are skipped by Normalized.
+var normalizedSkippedTypes = []string{
+	"BreadcrumbList", "ListItem", "ImageObject", "Organization", "Person", "SearchAction", "SiteNavigationElement",
+	"WebSite",
+}
+
+// Normalized returns the basic metadata of the page merged from the extracted syntaxes, in order of precedence:
+// JSON-LD, microdata, Open Graph, X Cards and the HTML metadata, which is parsed on demand if SyntaxHTML was not
+// extracted. A field missing from a syntax falls through to the next one, and Provenance records the syntax each
+// field was taken from.
+// From JSON-LD and microdata, the first node or item describing the page itself, not a breadcrumb, an organization
+// or the like, declaring a field is used, the site name is taken from a WebSite or a publisher. The Open Graph and
+// X Cards merged with SetMergeSocial are not used.
+func (e *Extractor) Normalized() PageMetadata {
+	pm := PageMetadata{Provenance: make(map[string]Syntax)}
+
+	for _, syntax := range normalizedSources {
+		pm.fill(e.pageMetadata(syntax), syntax)
+	}
+
+	return pm
+}
+
+// pageMetadata returns the basic metadata of the page declared by the extracted syntax.
+func (e *Extractor) pageMetadata(syntax Syntax) PageMetadata {
+	var pm PageMetadata
+
+	switch syntax {
+	case SyntaxJSONLD:
+		if nodes, ok := e.extracted[SyntaxJSONLD].([]map[string]any); ok {
+			pm = nodesPageMetadata(jsonLDGraphNodes(nodes))
+		}
+	case SyntaxMicrodata:
+		if items, ok := e.extracted[SyntaxMicrodata].([]extractor.MicrodataItem); ok {
+			var nodes []map[string]any
+			for i := range items {
+				nodes = append(nodes, microdataNode(&items[i]))
+			}
+			pm = nodesPageMetadata(nodes)
+		}
+	case SyntaxOpenGraph:
+		if og, ok := e.extracted[SyntaxOpenGraph].(*extractor.OpenGraph); ok {
+			pm = PageMetadata{Title: og.Title, Description: og.Description, URL: og.URL, SiteName: og.SiteName}
+			for _, image := range og.OpenGraphImage {
+				pm.Images = appendNonEmpty(pm.Images, image.URL)
+			}
+			if og.Article != nil {
+				pm.PublishedTime = og.Article.PublishedTime
+			}
+		}
+	case SyntaxXCards:
+		if xc, ok := e.extracted[SyntaxXCards].(*extractor.XCards); ok {
+			pm = PageMetadata{Title: xc.Title, Description: xc.Description, URL: xc.URL, SiteName: xc.SiteName}
+			for _, image := range xc.XCardsImage {
+				pm.Images = appendNonEmpty(pm.Images, image.URL)
+			}
+			for _, image := range xc.OpenGraphImage {
+				pm.Images = appendNonEmpty(pm.Images, image.URL)
+			}
+			if xc.Article != nil {
+				pm.PublishedTime = xc.Article.PublishedTime
+			}
+		}
+	case SyntaxHTML:
+		if hm := e.htmlMeta(); hm != nil {
+			pm = PageMetadata{Title: hm.Title, Description: hm.Description, URL: hm.Canonical}
+		}
+	}
+
+	return pm
+}
+
+// fill sets the empty fields of pm from the ones of source, recording syntax as their provenance.
+func (pm *PageMetadata) fill(source PageMetadata, syntax Syntax) {
+	if pm.Title == "" && source.Title != "" {
+		pm.Title = source.Title
+		pm.Provenance["title"] = syntax
+	}
+	if pm.Description == "" && source.Description != "" {
+		pm.Description = source.Description
+		pm.Provenance["description"] = syntax
+	}
+	if len(pm.Images) == 0 && len(source.Images) > 0 {
+		pm.Images = source.Images
+		pm.Provenance["images"] = syntax
+	}
+	if pm.URL == "" && source.URL != "" {
+		pm.URL = source.URL
+		pm.Provenance["url"] = syntax
+	}
+	if pm.SiteName == "" && source.SiteName != "" {
+		pm.SiteName = source.SiteName
+		pm.Provenance["site_name"] = syntax
+	}
+	if pm.PublishedTime.IsZero() && !source.PublishedTime.IsZero() {
+		pm.PublishedTime = source.PublishedTime
+		pm.Provenance["published_time"] = syntax
+	}
+}
+
+// nodesPageMetadata returns the basic metadata of the page declared by JSON-LD nodes, see Normalized.
+func nodesPageMetadata(nodes []map[string]any) PageMetadata {
+	pm := PageMetadata{Provenance: make(map[string]Syntax)}
+	var publisher string
+
+	for _, node := range nodes {
+		if website := extractor.DecodeWebSite(node); website != nil && pm.SiteName == "" {
+			pm.SiteName = website.Name
+		}
+		if isSkippedForNormalized(node) {
+			continue
+		}
+		work := extractor.DecodeCreativeWork(node)
+		if work == nil {
+			continue
+		}
+
+		title := work.Headline
+		if title == "" {
+			title = work.Name
+		}
+		pm.fill(PageMetadata{
+			Title:         title,
+			Description:   work.Description,
+			Images:        work.Image,
+			URL:           work.URL,
+			PublishedTime: work.DatePublished,
+		}, "")
+		if work.Publisher != nil && publisher == "" {
+			publisher = work.Publisher.Name
+		}
+	}
+	if pm.SiteName == "" {
+		pm.SiteName = publisher
+	}
+	pm.Provenance = nil
+
+	return pm
+}
+
+// isSkippedForNormalized reports whether node has one of the normalizedSkippedTypes.
+func isSkippedForNormalized(node map[string]any) bool {
+	for _, t := range extractor.JSONLDTypes(node) {
+		if contains(normalizedSkippedTypes, t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// microdataNode converts a microdata item into a JSON-LD like node, with its types under @type and its nested items
+// converted as well, so it can be decoded like JSON-LD.
+func microdataNode(item *extractor.MicrodataItem) map[string]any {
+	node := make(map[string]any, len(item.Properties)+1)
+
+	var types []any
+	for _, t := range microdataItemTypes(*item) {
+		types = append(types, t)
+	}
+	node["@type"] = types
+	for name := range item.Properties {
+		var values []any
+		for _, value := range item.Values(name) {
+			if nested, ok := value.(*extractor.MicrodataItem); ok {
+				values = append(values, microdataNode(nested))
+				continue
+			}
+			values = append(values, value)
+		}
+		if len(values) == 1 {
+			node[name] = values[0]
+		} else {
+			node[name] = values
+		}
+	}
+
+	return node
+}
+
+// appendNonEmpty appends s to list unless it is empty.
+func appendNonEmpty(list []string, s string) []string {
+	if s == "" {
+		return list
+	}
+
+	return append(list, s)
+}
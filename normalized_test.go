@@ -0,0 +1,92 @@
+package extract
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExtractor_Normalized(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		url     string
+		content string
+		want    PageMetadata
+	}{
+		{
+			name: "all syntaxes",
+			url:  fmt.Sprintf("%s/test-81-normalized.html", server.URL),
+			want: PageMetadata{
+				Title:         "The headline of JSON-LD",
+				Description:   "The description of Open Graph",
+				Images:        []string{"https://example.com/img/og.jpg"},
+				URL:           "https://example.com/news/jsonld",
+				SiteName:      "Example News",
+				PublishedTime: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+				Provenance: map[string]Syntax{
+					"title":          SyntaxJSONLD,
+					"description":    SyntaxOpenGraph,
+					"images":         SyntaxOpenGraph,
+					"url":            SyntaxJSONLD,
+					"site_name":      SyntaxJSONLD,
+					"published_time": SyntaxJSONLD,
+				},
+			},
+		},
+		{
+			name:    "microdata",
+			url:     "https://example.com/recipes/pie",
+			content: `<div itemscope itemtype="https://schema.org/Recipe"><h1 itemprop="name">Apple pie</h1><img itemprop="image" src="/img/pie.jpg"><span itemprop="publisher" itemscope itemtype="https://schema.org/Organization"><span itemprop="name">Example Recipes</span></span></div>`,
+			want: PageMetadata{
+				Title:    "Apple pie",
+				Images:   []string{"https://example.com/img/pie.jpg"},
+				SiteName: "Example Recipes",
+				Provenance: map[string]Syntax{
+					"title":     SyntaxMicrodata,
+					"images":    SyntaxMicrodata,
+					"site_name": SyntaxMicrodata,
+				},
+			},
+		},
+		{
+			name:    "HTML only",
+			url:     "https://example.com/plain",
+			content: `<html><head><title>Plain page</title><meta name="description" content="No structured data"><link rel="canonical" href="/plain"></head></html>`,
+			want: PageMetadata{
+				Title:       "Plain page",
+				Description: "No structured data",
+				URL:         "https://example.com/plain",
+				Provenance: map[string]Syntax{
+					"title":       SyntaxHTML,
+					"description": SyntaxHTML,
+					"url":         SyntaxHTML,
+				},
+			},
+		},
+		{
+			name:    "nothing",
+			url:     "https://example.com/empty",
+			content: `<html><body></body></html>`,
+			want:    PageMetadata{Provenance: map[string]Syntax{}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var content *string
+			if test.content != "" {
+				content = &test.content
+			}
+			e, err := New().Extract(test.url, content)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := e.Normalized(); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %+v, got %+v", test.want, got)
+			}
+		})
+	}
+}
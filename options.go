@@ -0,0 +1,36 @@
+package extract
+
+import "net/http"
+
+// Option configures an Extractor created with New.
+type Option func(*Extractor)
+
+// WithUserAgent sets the User-Agent header used when fetching, like SetUserAgent.
+func WithUserAgent(userAgent string) Option {
+	return func(e *Extractor) {
+		e.SetUserAgent(userAgent)
+	}
+}
+
+// WithFetchTimeout sets the fetch timeout in seconds, like SetFetchTimeout.
+func WithFetchTimeout(fetchTimeout uint8) Option {
+	return func(e *Extractor) {
+		e.SetFetchTimeout(fetchTimeout)
+	}
+}
+
+// WithSyntaxes sets the syntaxes to extract, like SetSyntaxes.
+func WithSyntaxes(syntaxes []Syntax) Option {
+	return func(e *Extractor) {
+		e.SetSyntaxes(syntaxes)
+	}
+}
+
+// WithHTTPClient sets the client used when fetching, like one with a custom transport or cookie jar. Its timeout takes
+// precedence over the fetch timeout if set, a client without a timeout, like http.DefaultClient, being bound by the
+// fetch timeout, and its redirect policy over the maximum redirects if set.
+func WithHTTPClient(client *http.Client) Option {
+	return func(e *Extractor) {
+		e.cfg.httpClient = client
+	}
+}
@@ -0,0 +1,104 @@
+package extract
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNew_options(t *testing.T) {
+	client := &http.Client{}
+
+	e := New(
+		WithUserAgent("test-agent"),
+		WithFetchTimeout(10),
+		WithSyntaxes([]Syntax{SyntaxJSONLD}),
+		WithHTTPClient(client),
+	)
+
+	if e.cfg.userAgent != "test-agent" {
+		t.Errorf("expected user agent %q, got %q", "test-agent", e.cfg.userAgent)
+	}
+	if e.cfg.fetchTimeout != 10 {
+		t.Errorf("expected fetch timeout %d, got %d", 10, e.cfg.fetchTimeout)
+	}
+	if !areSyntaxSlicesEqual(e.cfg.syntaxes, []Syntax{SyntaxJSONLD}) {
+		t.Errorf("expected syntaxes %v, got %v", []Syntax{SyntaxJSONLD}, e.cfg.syntaxes)
+	}
+	if e.cfg.httpClient != client {
+		t.Errorf("expected the given client, got %v", e.cfg.httpClient)
+	}
+}
+
+func TestNew_partialOptions(t *testing.T) {
+	e := New(WithFetchTimeout(10))
+	defaults := New()
+
+	if e.cfg.fetchTimeout != 10 {
+		t.Errorf("expected fetch timeout %d, got %d", 10, e.cfg.fetchTimeout)
+	}
+	if e.cfg.userAgent != defaults.cfg.userAgent || !areSyntaxSlicesEqual(e.cfg.syntaxes, defaults.cfg.syntaxes) || e.cfg.maxRedirects != defaults.cfg.maxRedirects {
+		t.Errorf("expected the defaults of the other fields, got %v", e.cfg)
+	}
+}
+
+// countingTransport counts the requests sent through it.
+type countingTransport struct {
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	transport := &countingTransport{}
+	e, err := New(WithHTTPClient(&http.Client{Transport: transport})).Extract(fmt.Sprintf("%s/redirect/test-01-opengraph-minimal.html", server.URL), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if transport.requests != 2 {
+		t.Errorf("expected 2 requests through the client, got %d", transport.requests)
+	}
+	if _, ok := e.GetExtracted()[SyntaxOpenGraph]; !ok {
+		t.Errorf("expected Open Graph metadata, got %v", e.GetExtracted())
+	}
+}
+
+func TestWithHTTPClient_timeout(t *testing.T) {
+	content := `<html><head><meta property="og:title" content="Title"></head></html>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(1500 * time.Millisecond)
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	// the timeout of the client takes precedence over the fetch timeout
+	e, err := New(WithHTTPClient(&http.Client{Timeout: 5 * time.Second}), WithFetchTimeout(1)).Extract(server.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.OpenGraph() == nil {
+		t.Errorf("expected Open Graph metadata, got %v", e.GetExtracted())
+	}
+
+	// a client without a timeout is bound by the fetch timeout
+	client := &http.Client{}
+	if _, err := New(WithHTTPClient(client), WithFetchTimeout(1)).Extract(server.URL, nil); err == nil {
+		t.Errorf("expected the fetch timeout to apply to a client without a timeout")
+	}
+	if client.Timeout != 0 {
+		t.Errorf("expected the given client to be unmodified, got a timeout of %v", client.Timeout)
+	}
+
+	if _, err := New(WithFetchTimeout(1)).Extract(server.URL, nil); err == nil {
+		t.Errorf("expected the fetch timeout to apply without a client")
+	}
+}
@@ -0,0 +1,63 @@
+package extract
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ParserFunc is the signature of a syntax parser: it receives the page URL and its HTML content and returns the
+// extracted data together with any errors encountered while parsing.
+type ParserFunc func(url, content string) (any, []error)
+
+var (
+	// registryMu guards registry.
+	registryMu sync.RWMutex
+
+	// registry holds the custom syntax parsers registered with RegisterSyntax.
+	registry = make(map[Syntax]ParserFunc)
+)
+
+// RegisterSyntax registers a custom parser under the given syntax name. A registered syntax is not enabled by
+// default, it has to be selected with SetSyntaxes, after which Extract runs it alongside the built-in parsers and
+// stores its result under name.
+// Registering the same name twice replaces the previous parser. It panics if name is empty, one of the built-in
+// syntaxes or SyntaxSocial, or if fn is nil.
+func RegisterSyntax(name Syntax, fn func(url, content string) (any, []error)) {
+	if name == "" {
+		panic("extract: RegisterSyntax name is empty")
+	}
+	if fn == nil {
+		panic(fmt.Sprintf("extract: RegisterSyntax parser for %q is nil", name))
+	}
+	if isBuiltinSyntax(name) || name == SyntaxSocial {
+		panic(fmt.Sprintf("extract: RegisterSyntax cannot override built-in syntax %q", name))
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = fn
+}
+
+// registeredParser returns the custom parser registered under name, if any.
+func registeredParser(name Syntax) (ParserFunc, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	fn, ok := registry[name]
+
+	return fn, ok
+}
+
+// isBuiltinSyntax reports whether syntax is one of the syntaxes parsed by the package itself.
+func isBuiltinSyntax(syntax Syntax) bool {
+	return contains(SYNTAXES, syntax) || contains(optionalSyntaxes, syntax)
+}
+
+// isSupportedSyntax reports whether syntax is a built-in or a registered syntax.
+func isSupportedSyntax(syntax Syntax) bool {
+	if isBuiltinSyntax(syntax) {
+		return true
+	}
+	_, ok := registeredParser(syntax)
+
+	return ok
+}
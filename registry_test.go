@@ -0,0 +1,90 @@
+package extract
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegisterSyntax(t *testing.T) {
+	const syntaxCustom Syntax = "test-custom"
+
+	RegisterSyntax(syntaxCustom, func(url, content string) (any, []error) {
+		return map[string]string{"url": url, "content": content}, nil
+	})
+
+	tests := []struct {
+		name     string
+		syntaxes []Syntax
+		want     map[Syntax]any
+	}{
+		{
+			name:     "registered syntax not selected",
+			syntaxes: []Syntax{SyntaxOpenGraph},
+			want: map[Syntax]any{
+				SyntaxOpenGraph: nil,
+			},
+		},
+		{
+			name:     "registered syntax selected",
+			syntaxes: []Syntax{SyntaxOpenGraph, syntaxCustom},
+			want: map[Syntax]any{
+				SyntaxOpenGraph: nil,
+				syntaxCustom: map[string]string{
+					"url":     "https://example.com/",
+					"content": "<html></html>",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, err := New().SetSyntaxes(test.syntaxes).Extract("https://example.com/", pointerOfString("<html></html>"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(e.GetExtracted(), test.want) {
+				t.Errorf("expected %v, got %v", test.want, e.GetExtracted())
+			}
+		})
+	}
+}
+
+func TestRegisterSyntax_panics(t *testing.T) {
+	parser := func(url, content string) (any, []error) {
+		return nil, nil
+	}
+
+	tests := []struct {
+		name   string
+		syntax Syntax
+		parser func(url, content string) (any, []error)
+	}{
+		{
+			name:   "empty name",
+			syntax: "",
+			parser: parser,
+		},
+		{
+			name:   "nil parser",
+			syntax: "test-nil",
+			parser: nil,
+		},
+		{
+			name:   "built-in syntax",
+			syntax: SyntaxOpenGraph,
+			parser: parser,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic, got none")
+				}
+			}()
+			RegisterSyntax(test.syntax, test.parser)
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package extract
+
+import (
+	extractor "github.com/aafeher/go-microdata-extract/extractors"
+	"reflect"
+)
+
+type (
+	// Social represents the merged Open Graph and X Cards metadata of a page, keyed by the property name without its
+	// og: or twitter: prefix, like "title" or "image". Only the basic properties and the media URLs are merged, the
+	// rest of the metadata is kept under the opengraph and xcards keys, see mergeSocial.
+	Social map[string][]SocialValue
+
+	// SocialValue represents a distinct value of a Social property and the syntaxes that declared it.
+	SocialValue struct {
+		Value   string   `json:"value"`
+		Sources []Syntax `json:"sources"`
+	}
+)
+
+// mergeSocial merges the extracted Open Graph and X Cards metadata into a Social object. The opengraph and xcards
+// keys keep the metadata not merged, like the article properties or the structured media properties, and are
+// removed if nothing remains.
+func (e *Extractor) mergeSocial() {
+	_, hasOpenGraph := e.extracted[SyntaxOpenGraph]
+	_, hasXCards := e.extracted[SyntaxXCards]
+	if !hasOpenGraph && !hasXCards {
+		return
+	}
+
+	og, _ := e.extracted[SyntaxOpenGraph].(*extractor.OpenGraph)
+	xc, _ := e.extracted[SyntaxXCards].(*extractor.XCards)
+	delete(e.extracted, SyntaxOpenGraph)
+	delete(e.extracted, SyntaxXCards)
+	if rest := openGraphRemainder(og); rest != nil {
+		e.extracted[SyntaxOpenGraph] = rest
+	}
+	if rest := xCardsRemainder(xc); rest != nil {
+		e.extracted[SyntaxXCards] = rest
+	}
+
+	var result any
+	if social := newSocial(og, xc); social != nil {
+		result = social
+	}
+	e.extracted[SyntaxSocial] = result
+}
+
+// openGraphRemainder returns a copy of og without the properties merged by newSocial, keeping the media elements
+// with structured properties besides their URL. It returns nil if nothing remains.
+func openGraphRemainder(og *extractor.OpenGraph) *extractor.OpenGraph {
+	if og == nil {
+		return nil
+	}
+
+	rest := *og
+	rest.Type, rest.Title, rest.URL = "", "", ""
+	rest.Description, rest.Determiner, rest.Locale, rest.LocaleAlternate, rest.SiteName = "", "", "", nil, ""
+	rest.OpenGraphImage = remainingMedia(og.OpenGraphImage, func(image extractor.OpenGraphImage) extractor.OpenGraphImage {
+		return extractor.OpenGraphImage{URL: image.URL}
+	})
+	rest.OpenGraphVideo = remainingMedia(og.OpenGraphVideo, func(video extractor.OpenGraphVideo) extractor.OpenGraphVideo {
+		return extractor.OpenGraphVideo{URL: video.URL}
+	})
+	rest.OpenGraphAudio = remainingMedia(og.OpenGraphAudio, func(audio extractor.OpenGraphAudio) extractor.OpenGraphAudio {
+		return extractor.OpenGraphAudio{URL: audio.URL}
+	})
+	if reflect.DeepEqual(rest, extractor.OpenGraph{}) {
+		return nil
+	}
+
+	return &rest
+}
+
+// xCardsRemainder returns a copy of xc without the properties merged by newSocial, like openGraphRemainder.
+func xCardsRemainder(xc *extractor.XCards) *extractor.XCards {
+	if xc == nil {
+		return nil
+	}
+
+	rest := *xc
+	rest.Card, rest.Site, rest.Creator = "", "", ""
+	rest.Type, rest.Title, rest.URL = "", "", ""
+	rest.Description, rest.Determiner, rest.Locale, rest.LocaleAlternate, rest.SiteName = "", "", "", nil, ""
+	rest.XCardsImage = remainingMedia(xc.XCardsImage, func(image extractor.XCardsImage) extractor.XCardsImage {
+		return extractor.XCardsImage{URL: image.URL}
+	})
+	rest.XCardsVideo = remainingMedia(xc.XCardsVideo, func(video extractor.XCardsVideo) extractor.XCardsVideo {
+		return extractor.XCardsVideo{URL: video.URL}
+	})
+	rest.XCardsAudio = remainingMedia(xc.XCardsAudio, func(audio extractor.XCardsAudio) extractor.XCardsAudio {
+		return extractor.XCardsAudio{URL: audio.URL}
+	})
+	if reflect.DeepEqual(rest, extractor.XCards{}) {
+		return nil
+	}
+
+	return &rest
+}
+
+// remainingMedia returns the media elements holding more than their URL, the URL being merged into the Social
+// object. urlOnly returns an element reduced to its URL.
+func remainingMedia[M comparable](media []M, urlOnly func(M) M) []M {
+	var rest []M
+	for _, element := range media {
+		if element != urlOnly(element) {
+			rest = append(rest, element)
+		}
+	}
+
+	return rest
+}
+
+// newSocial merges the Open Graph and X Cards metadata. It returns nil if neither declares a merged property.
+func newSocial(og *extractor.OpenGraph, xc *extractor.XCards) Social {
+	social := make(Social)
+
+	if og != nil {
+		social.add("type", og.Type, SyntaxOpenGraph)
+		social.add("title", og.Title, SyntaxOpenGraph)
+		social.add("url", og.URL, SyntaxOpenGraph)
+		social.add("description", og.Description, SyntaxOpenGraph)
+		social.add("determiner", og.Determiner, SyntaxOpenGraph)
+		social.add("locale", og.Locale, SyntaxOpenGraph)
+		for _, locale := range og.LocaleAlternate {
+			social.add("locale:alternate", locale, SyntaxOpenGraph)
+		}
+		social.add("site_name", og.SiteName, SyntaxOpenGraph)
+		for _, image := range og.OpenGraphImage {
+			social.add("image", image.URL, SyntaxOpenGraph)
+		}
+		for _, video := range og.OpenGraphVideo {
+			social.add("video", video.URL, SyntaxOpenGraph)
+		}
+		for _, audio := range og.OpenGraphAudio {
+			social.add("audio", audio.URL, SyntaxOpenGraph)
+		}
+	}
+
+	if xc != nil {
+		social.add("card", xc.Card, SyntaxXCards)
+		social.add("site", xc.Site, SyntaxXCards)
+		social.add("creator", xc.Creator, SyntaxXCards)
+		social.add("type", xc.Type, SyntaxXCards)
+		social.add("title", xc.Title, SyntaxXCards)
+		social.add("url", xc.URL, SyntaxXCards)
+		social.add("description", xc.Description, SyntaxXCards)
+		social.add("determiner", xc.Determiner, SyntaxXCards)
+		social.add("locale", xc.Locale, SyntaxXCards)
+		for _, locale := range xc.LocaleAlternate {
+			social.add("locale:alternate", locale, SyntaxXCards)
+		}
+		social.add("site_name", xc.SiteName, SyntaxXCards)
+		for _, image := range xc.XCardsImage {
+			social.add("image", image.URL, SyntaxXCards)
+		}
+		for _, video := range xc.XCardsVideo {
+			social.add("video", video.URL, SyntaxXCards)
+		}
+		for _, audio := range xc.XCardsAudio {
+			social.add("audio", audio.URL, SyntaxXCards)
+		}
+	}
+
+	if len(social) == 0 {
+		return nil
+	}
+
+	return social
+}
+
+// add records value of property as declared by source. An empty value is ignored.
+func (s Social) add(property, value string, source Syntax) {
+	if value == "" {
+		return
+	}
+	for i := range s[property] {
+		if s[property][i].Value == value {
+			if !contains(s[property][i].Sources, source) {
+				s[property][i].Sources = append(s[property][i].Sources, source)
+			}
+			return
+		}
+	}
+	s[property] = append(s[property], SocialValue{Value: value, Sources: []Syntax{source}})
+}
@@ -0,0 +1,102 @@
+package extract
+
+import (
+	"fmt"
+	extractor "github.com/aafeher/go-microdata-extract/extractors"
+	"reflect"
+	"testing"
+)
+
+func TestExtractor_SetMergeSocial(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/test-48-social-og-xcards.html", server.URL)
+
+	t.Run("disabled", func(t *testing.T) {
+		e, err := New().Extract(url, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		extracted := e.GetExtracted()
+		if extracted[SyntaxOpenGraph] == nil || extracted[SyntaxXCards] == nil {
+			t.Errorf("expected separate opengraph and xcards, got %v", extracted)
+		}
+		if _, ok := extracted[SyntaxSocial]; ok {
+			t.Errorf("expected no social key, got %v", extracted[SyntaxSocial])
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		e, err := New().SetMergeSocial(true).Extract(url, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		extracted := e.GetExtracted()
+		if _, ok := extracted[SyntaxOpenGraph]; ok {
+			t.Errorf("expected no opengraph key, got %v", extracted[SyntaxOpenGraph])
+		}
+		if _, ok := extracted[SyntaxXCards]; ok {
+			t.Errorf("expected no xcards key, got %v", extracted[SyntaxXCards])
+		}
+
+		both := []Syntax{SyntaxOpenGraph, SyntaxXCards}
+		want := Social{
+			"type":  {{Value: "website", Sources: []Syntax{SyntaxOpenGraph}}},
+			"title": {{Value: "The Rock", Sources: both}},
+			"url":   {{Value: "https://www.imdb.com/title/tt0117500/", Sources: []Syntax{SyntaxOpenGraph}}},
+			"description": {
+				{Value: "A mild-mannered chemist and an ex-con must lead the counterstrike.", Sources: []Syntax{SyntaxOpenGraph}},
+				{Value: "The Rock (1996)", Sources: []Syntax{SyntaxXCards}},
+			},
+			"image": {{Value: "https://ia.media-imdb.com/images/rock.jpg", Sources: both}},
+			"card":  {{Value: "summary_large_image", Sources: []Syntax{SyntaxXCards}}},
+			"site":  {{Value: "@imdb", Sources: []Syntax{SyntaxXCards}}},
+		}
+		if got := extracted[SyntaxSocial]; !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("enabled with structured metadata", func(t *testing.T) {
+		e, err := New().SetMergeSocial(true).Extract(fmt.Sprintf("%s/test-103-social-og-xcards-structured.html", server.URL), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		wantOpenGraph := &extractor.OpenGraph{
+			TTL:            3600,
+			OpenGraphImage: []extractor.OpenGraphImage{{URL: "https://example.com/img/anvil.jpg", Width: 1200}},
+			Article:        &extractor.Article{Section: "Tools"},
+			Extra:          map[string][]string{"fb:app_id": {"174829003346"}},
+		}
+		if got := e.OpenGraph(); !reflect.DeepEqual(got, wantOpenGraph) {
+			t.Errorf("expected the Open Graph remainder %+v, got %+v", wantOpenGraph, got)
+		}
+		wantXCards := &extractor.XCards{
+			Player: &extractor.XCardsPlayer{URL: "https://example.com/player", Width: 640},
+		}
+		if got := e.XCards(); !reflect.DeepEqual(got, wantXCards) {
+			t.Errorf("expected the X Cards remainder %+v, got %+v", wantXCards, got)
+		}
+
+		both := []Syntax{SyntaxOpenGraph, SyntaxXCards}
+		wantImages := []SocialValue{
+			{Value: "https://example.com/img/anvil.jpg", Sources: both},
+			{Value: "https://example.com/img/plain.jpg", Sources: []Syntax{SyntaxOpenGraph}},
+		}
+		if got := e.GetExtracted()[SyntaxSocial].(Social)["image"]; !reflect.DeepEqual(got, wantImages) {
+			t.Errorf("expected images %v, got %v", wantImages, got)
+		}
+	})
+
+	t.Run("enabled without social metadata", func(t *testing.T) {
+		e, err := New().SetMergeSocial(true).Extract("https://example.com/", pointerOfString("<html></html>"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got, ok := e.GetExtracted()[SyntaxSocial]; !ok || got != nil {
+			t.Errorf("expected nil social, got %v", got)
+		}
+	})
+}
@@ -11,6 +11,7 @@ import (
 // testServer creates a test server with a custom request handler that serves static files and dynamically replaces
 // the "HOST" string in the response with the value of the request's Host header. The server handles the following routes:
 //   - "/" returns a 404 Not Found response.
+//   - "/redirect-to-pdf" redirects to "/document.pdf", which returns a PDF document.
 //   - other routes serve static files located in the "./test" directory. If a file contains the "HOST" string,
 //     it will be replaced with the request's Host value. The modified response will be sent back to the client.
 //
@@ -28,7 +29,29 @@ func testServer() *httptest.Server {
 			return
 		}
 
-		res, err := os.ReadFile("./test" + r.RequestURI)
+		if r.RequestURI == "/redirect-to-pdf" {
+			http.Redirect(w, r, "/document.pdf", http.StatusFound)
+			return
+		}
+		if r.RequestURI == "/redirect-loop" {
+			http.Redirect(w, r, "/redirect-loop", http.StatusFound)
+			return
+		}
+		if strings.HasPrefix(r.RequestURI, "/redirect/") {
+			http.Redirect(w, r, "/catalog/"+strings.TrimPrefix(r.RequestURI, "/redirect/"), http.StatusMovedPermanently)
+			return
+		}
+		if strings.HasPrefix(r.RequestURI, "/catalog/") {
+			r.URL.Path = strings.TrimPrefix(r.URL.Path, "/catalog")
+		}
+		if r.RequestURI == "/document.pdf" {
+			w.Header().Set("Content-Type", "application/pdf")
+			w.WriteHeader(http.StatusOK)
+			_, _ = fmt.Fprintln(w, "%PDF-1.4")
+			return
+		}
+
+		res, err := os.ReadFile("./test" + r.URL.Path)
 		if err != nil {
 			http.NotFound(w, r)
 			return
@@ -36,6 +59,10 @@ func testServer() *httptest.Server {
 
 		strRes := string(res)
 		strRes = strings.Replace(strRes, "HOST", r.Host, -1)
+		w.Header().Set("Last-Modified", "Wed, 01 Jan 2025 00:00:00 GMT")
+		if contentType := r.URL.Query().Get("content-type"); contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
 		w.WriteHeader(http.StatusOK)
 		_, _ = fmt.Fprintln(w, strRes)
 	}))
@@ -0,0 +1,185 @@
+package extract
+
+import (
+	"fmt"
+	extractor "github.com/aafeher/go-microdata-extract/extractors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// rdfType is the predicate of the type triples.
+const rdfType = "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"
+
+// Triple represents an RDF statement. Subject is an IRI or a blank node like "_:b0", Predicate is an IRI and Object
+// is an IRI, a blank node or, if Literal is set, a literal value.
+type Triple struct {
+	Subject   string `json:"subject"`
+	Predicate string `json:"predicate"`
+	Object    string `json:"object"`
+	Literal   bool   `json:"literal,omitempty"`
+}
+
+// String returns the triple as an N-Triples line.
+func (t Triple) String() string {
+	object := ntriplesTerm(t.Object)
+	if t.Literal {
+		object = strconv.Quote(t.Object)
+	}
+
+	return fmt.Sprintf("%s %s %s .", ntriplesTerm(t.Subject), ntriplesTerm(t.Predicate), object)
+}
+
+// ntriplesTerm returns a blank node as is and an IRI enclosed in angle brackets.
+func ntriplesTerm(term string) string {
+	if strings.HasPrefix(term, "_:") {
+		return term
+	}
+
+	return "<" + term + ">"
+}
+
+// tripleBuilder converts the extracted items into triples, numbering the blank nodes of items without an identifier.
+type tripleBuilder struct {
+	triples    []Triple
+	blankNodes int
+}
+
+// Triples returns the extracted JSON-LD nodes and microdata items as RDF triples. The @id or itemid of an item is its
+// subject, a blank node is used if it has none. The property names are the predicates, expanded with the vocabulary
+// of the item: the string @context of a JSON-LD node, or the namespace of the itemtype of a microdata item.
+// Nested items are linked by their subject and converted as well.
+func (e *Extractor) Triples() []Triple {
+	b := &tripleBuilder{}
+
+	if nodes, ok := e.extracted[SyntaxJSONLD].([]map[string]any); ok {
+		for _, node := range nodes {
+			b.jsonLDNode(node, "")
+		}
+	}
+	if items, ok := e.extracted[SyntaxMicrodata].([]extractor.MicrodataItem); ok {
+		for i := range items {
+			b.microdataItem(&items[i], "")
+		}
+	}
+
+	return b.triples
+}
+
+// subject returns id, or a new blank node if it is empty.
+func (b *tripleBuilder) subject(id string) string {
+	if id = strings.TrimSpace(id); id != "" {
+		return id
+	}
+	subject := fmt.Sprintf("_:b%d", b.blankNodes)
+	b.blankNodes++
+
+	return subject
+}
+
+// add appends a triple.
+func (b *tripleBuilder) add(subject, predicate, object string, literal bool) {
+	b.triples = append(b.triples, Triple{Subject: subject, Predicate: predicate, Object: object, Literal: literal})
+}
+
+// jsonLDNode converts a JSON-LD node, whose vocabulary is inherited from its parent unless it declares its own
+// @context, and returns its subject.
+func (b *tripleBuilder) jsonLDNode(node map[string]any, vocab string) string {
+	if context, ok := node["@context"].(string); ok {
+		vocab = context
+	}
+	id, _ := node["@id"].(string)
+	subject := b.subject(id)
+
+	if graph, ok := node["@graph"].([]any); ok {
+		for _, member := range graph {
+			if m, ok := member.(map[string]any); ok {
+				b.jsonLDNode(m, vocab)
+			}
+		}
+	}
+	for _, t := range extractor.JSONLDTypes(node) {
+		b.add(subject, rdfType, expandTerm(vocab, t), false)
+	}
+	for _, key := range sortedKeys(node) {
+		if strings.HasPrefix(key, "@") {
+			continue
+		}
+		for _, value := range asSlice(node[key]) {
+			switch value := value.(type) {
+			case map[string]any:
+				b.add(subject, expandTerm(vocab, key), b.jsonLDNode(value, vocab), false)
+			case string:
+				b.add(subject, expandTerm(vocab, key), value, true)
+			case float64:
+				b.add(subject, expandTerm(vocab, key), strconv.FormatFloat(value, 'f', -1, 64), true)
+			case bool:
+				b.add(subject, expandTerm(vocab, key), strconv.FormatBool(value), true)
+			}
+		}
+	}
+
+	return subject
+}
+
+// microdataItem converts a microdata item, whose vocabulary is inherited from its parent unless it has an itemtype,
+// and returns its subject.
+func (b *tripleBuilder) microdataItem(item *extractor.MicrodataItem, vocab string) string {
+	id := ""
+	if item.ID != nil {
+		id = *item.ID
+	}
+	subject := b.subject(id)
+
+	types := microdataItemTypes(*item)
+	if len(types) > 0 {
+		vocab = microdataVocabulary(types[0])
+	}
+	for _, t := range types {
+		b.add(subject, rdfType, t, false)
+	}
+	for _, key := range sortedKeys(item.Properties) {
+		for _, value := range item.Values(key) {
+			switch value := value.(type) {
+			case *extractor.MicrodataItem:
+				b.add(subject, expandTerm(vocab, key), b.microdataItem(value, vocab), false)
+			case string:
+				b.add(subject, expandTerm(vocab, key), value, true)
+			}
+		}
+	}
+
+	return subject
+}
+
+// microdataVocabulary returns the namespace of an itemtype, up to its last '#' or '/'.
+func microdataVocabulary(itemType string) string {
+	if i := strings.LastIndexAny(itemType, "#/"); i >= 0 {
+		return itemType[:i+1]
+	}
+
+	return ""
+}
+
+// expandTerm returns term prefixed with the vocabulary, unless it is already an IRI or there is no vocabulary.
+func expandTerm(vocab, term string) string {
+	if vocab == "" || strings.Contains(term, "://") {
+		return term
+	}
+	if !strings.HasSuffix(vocab, "/") && !strings.HasSuffix(vocab, "#") {
+		vocab += "/"
+	}
+
+	return vocab + term
+}
+
+// sortedKeys returns the keys of m in sorted order, for a deterministic output.
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,79 @@
+package extract
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestExtractor_Triples(t *testing.T) {
+	server := testServer()
+	defer server.Close()
+
+	t.Run("microdata", func(t *testing.T) {
+		e, err := New().Extract(fmt.Sprintf("%s/test-36-w3cmicrodata-organization.html", server.URL), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []Triple{
+			{Subject: "http://example.com/org/1", Predicate: rdfType, Object: "http://schema.org/Organization"},
+			{Subject: "http://example.com/person/1", Predicate: rdfType, Object: "http://schema.org/Person"},
+			{Subject: "http://example.com/person/1", Predicate: "http://schema.org/name", Object: "John Doe", Literal: true},
+			{Subject: "http://example.com/org/1", Predicate: "http://schema.org/employee", Object: "http://example.com/person/1"},
+			{Subject: "http://example.com/org/1", Predicate: "http://schema.org/name", Object: "Example Organization", Literal: true},
+		}
+		if got := e.Triples(); !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("JSON-LD", func(t *testing.T) {
+		content := `<script type="application/ld+json">
+{"@context": "https://schema.org", "@type": "Book", "name": "Moby Dick", "numberOfPages": 635,
+ "author": {"@type": "Person", "name": "Herman Melville"}}
+</script>`
+		e, err := New().Extract("https://example.com/", &content)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := []Triple{
+			{Subject: "_:b0", Predicate: rdfType, Object: "https://schema.org/Book"},
+			{Subject: "_:b1", Predicate: rdfType, Object: "https://schema.org/Person"},
+			{Subject: "_:b1", Predicate: "https://schema.org/name", Object: "Herman Melville", Literal: true},
+			{Subject: "_:b0", Predicate: "https://schema.org/author", Object: "_:b1"},
+			{Subject: "_:b0", Predicate: "https://schema.org/name", Object: "Moby Dick", Literal: true},
+			{Subject: "_:b0", Predicate: "https://schema.org/numberOfPages", Object: "635", Literal: true},
+		}
+		if got := e.Triples(); !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+}
+
+func TestTriple_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		triple Triple
+		want   string
+	}{
+		{
+			name:   "IRI object",
+			triple: Triple{Subject: "http://example.com/org/1", Predicate: rdfType, Object: "http://schema.org/Organization"},
+			want:   `<http://example.com/org/1> <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <http://schema.org/Organization> .`,
+		},
+		{
+			name:   "literal object of a blank node",
+			triple: Triple{Subject: "_:b0", Predicate: "http://schema.org/name", Object: `"Example" Organization`, Literal: true},
+			want:   `_:b0 <http://schema.org/name> "\"Example\" Organization" .`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.triple.String(); got != test.want {
+				t.Errorf("expected %s, got %s", test.want, got)
+			}
+		})
+	}
+}